@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	approveEmoji = "✅"
+	rejectEmoji  = "❌"
+)
+
+//queueApproval sends a message to guild's approval channel instead of the board. Returns true if the message is waiting for approval or was rejected.
+func (se *StarboardEvent) queueApproval(react *discordgo.MessageReactions, ch *discordgo.Channel) (bool, error) {
+	if se.guild.ApprovalChannel == "" {
+		return false, nil
+	}
+
+	pair := database.NewPair(se.message.ChannelID, se.message.ID)
+	pending, err := database.FindPending(&pair, se.target.Name)
+	if err != nil {
+		return false, fmt.Errorf("database.FindPending(): %v", err)
+	}
+
+	if pending != nil {
+		return pending.Status != database.ApprovalApproved, nil
+	}
+
+	embed, err := se.createEmbed(react, ch)
+	if err != nil {
+		return false, err
+	}
+
+	if embed == nil {
+		return true, nil
+	}
+	defer embed.Close()
+
+	review := &discordgo.MessageEmbed{
+		Title:       "Pending approval",
+		Description: fmt.Sprintf("**Board:** %v\n**Stars:** %v\nReact with %v to post the message or %v to reject it.", se.target.DisplayName(), react.Count, approveEmoji, rejectEmoji),
+		Color:       utils.EmbedColor,
+		Timestamp:   utils.EmbedTimestamp(),
+	}
+
+	msg, err := sendChannelMessage(se.session, se.guild.ApprovalChannel, append(embed.Embeds, review), embed.Files)
+	if err != nil {
+		return false, err
+	}
+
+	logrus.Infof("Queued message %v in channel %v for approval. Guild: %v, board: %v", se.message.ID, se.message.ChannelID, se.guild.Name, se.target.DisplayName())
+	for _, emoji := range []string{approveEmoji, rejectEmoji} {
+		err := se.session.MessageReactionAdd(msg.ChannelID, msg.ID, emoji)
+		if err != nil {
+			logrus.Warnf("queueApproval() -> s.MessageReactionAdd(): %v", err)
+		}
+	}
+
+	reviewPair := database.NewPair(msg.ChannelID, msg.ID)
+	err = database.InsertPending(database.NewPending(&pair, &reviewPair, se.guild.ID, se.target.Name))
+	if err != nil {
+		return false, fmt.Errorf("database.InsertPending(): %v", err)
+	}
+
+	return true, nil
+}
+
+//approveStarboard posts an approved message to the board.
+func (se *StarboardEvent) approveStarboard() error {
+	if se.isStarboarded() {
+		return nil
+	}
+
+	err := se.countVotes()
+	if err != nil {
+		return err
+	}
+
+	react := se.React
+	if react == nil {
+		react = &discordgo.MessageReactions{Count: 0, Emoji: boardEmoji(se.target)}
+	}
+
+	ch, err := se.session.Channel(se.message.ChannelID)
+	if err != nil {
+		return err
+	}
+
+	return se.postStarboard(react, ch, false)
+}
+
+//reviewReacted approves or rejects a queued message when a moderator reacts to its review message.
+func reviewReacted(s *discordgo.Session, guild *database.Guild, r *discordgo.MessageReactionAdd) {
+	if r.UserID == s.State.User.ID {
+		return
+	}
+
+	var status string
+	switch r.Emoji.Name {
+	case approveEmoji:
+		status = database.ApprovalApproved
+	case rejectEmoji:
+		status = database.ApprovalRejected
+	default:
+		return
+	}
+
+	pending, err := database.PendingByReview(r.ChannelID, r.MessageID)
+	if err != nil {
+		logrus.Warnf("reviewReacted() -> database.PendingByReview(): %v", err)
+		return
+	}
+
+	if pending == nil || pending.Status != database.ApprovalPending {
+		return
+	}
+
+	ok, err := utils.MemberHasPermission(s, r.GuildID, r.UserID, discordgo.PermissionAdministrator|discordgo.PermissionManageServer|discordgo.PermissionManageMessages)
+	if err != nil || !ok {
+		s.MessageReactionRemove(r.ChannelID, r.MessageID, r.Emoji.APIName(), r.UserID)
+		return
+	}
+
+	board := guild.FindBoard(pending.Board)
+	if board == nil {
+		logrus.Warnf("reviewReacted(): board %v doesn't exist anymore", pending.Board)
+		return
+	}
+
+	reviewed, err := database.ReviewPending(pending.Review, status, r.UserID)
+	if err != nil {
+		logrus.Warnf("reviewReacted() -> database.ReviewPending(): %v", err)
+		return
+	}
+
+	if !reviewed {
+		return
+	}
+
+	verdict := &discordgo.MessageEmbed{
+		Color:     utils.EmbedColor,
+		Timestamp: utils.EmbedTimestamp(),
+	}
+
+	if status == database.ApprovalApproved {
+		verdict.Title = approveEmoji + " Approved"
+		verdict.Description = fmt.Sprintf("[Message](%v) was approved for %v board by <@%v>.", pending.Original.Link(guild.ID), board.DisplayName(), r.UserID)
+
+		msg, err := s.ChannelMessage(pending.Original.ChannelID, pending.Original.MessageID)
+		if err == nil {
+			se := newStarboardEventAction(s, guild.ID, msg, board, actionApprove)
+			se.userID = r.UserID
+			err = se.await()
+		}
+
+		if err != nil {
+			logrus.Warnf("reviewReacted(): failed to post approved message %v: %v", pending.Original.MessageID, err)
+			verdict.Description += fmt.Sprintf("\nFailed to post the message: %v", err)
+		}
+	} else {
+		verdict.Title = rejectEmoji + " Rejected"
+		verdict.Description = fmt.Sprintf("[Message](%v) was rejected from %v board by <@%v>.", pending.Original.Link(guild.ID), board.DisplayName(), r.UserID)
+	}
+
+	err = editChannelMessage(s, r.ChannelID, r.MessageID, []*discordgo.MessageEmbed{verdict})
+	if err != nil {
+		logrus.Warnf("reviewReacted() -> editChannelMessage(): %v", err)
+	}
+
+	err = s.MessageReactionsRemoveAll(r.ChannelID, r.MessageID)
+	if err != nil {
+		logrus.Warnf("reviewReacted() -> s.MessageReactionsRemoveAll(): %v", err)
+	}
+}
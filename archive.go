@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+)
+
+//showEntry posts a starboard entry to a channel. Entries are re-rendered from their original messages, or from snapshots if originals were deleted. Returns false if an entry has neither.
+func showEntry(s *discordgo.Session, channelID string, entry *database.Message) (bool, error) {
+	guild := database.GuildCache[entry.GuildID]
+
+	original, err := s.ChannelMessage(entry.Original.ChannelID, entry.Original.MessageID)
+	if err != nil {
+		if entry.Snapshot == nil || guild.SpoilerMode() == database.SpoilerSkip && entry.Snapshot.HasSpoiler() {
+			return false, nil
+		}
+
+		_, err := s.ChannelMessageSendEmbed(channelID, snapshotEmbed(guild, entry, entry.Snapshot, false))
+		return err == nil, err
+	}
+
+	if guild.SpoilerMode() == database.SpoilerSkip && utils.HasSpoiler(original) {
+		return false, nil
+	}
+
+	ch, err := s.Channel(original.ChannelID)
+	if err != nil {
+		return false, err
+	}
+
+	board := guild.FindBoard(entry.Board)
+	if board == nil {
+		board = guild.DefaultBoard()
+	}
+
+	se := &StarboardEvent{guild: guild, target: board, message: original, session: s}
+	msg, err := se.createEmbed(&discordgo.MessageReactions{Count: entry.Stars, Emoji: boardEmoji(board)}, ch)
+	if err != nil {
+		return false, err
+	}
+
+	if msg == nil {
+		return false, nil
+	}
+	defer msg.Close()
+
+	msg.Embed().Fields = append(msg.Embed().Fields, &discordgo.MessageEmbedField{
+		Name:   "Starboard",
+		Value:  fmt.Sprintf("[Click here desu~](%v)", entry.Starboard.Link(guild.ID)),
+		Inline: true,
+	})
+
+	_, err = sendChannelMessage(s, channelID, msg.Embeds, msg.Files)
+	return err == nil, err
+}
+
+//snapshotEmbed renders a starboard entry from a snapshot of its original message.
+func snapshotEmbed(guild *database.Guild, entry *database.Message, snapshot *database.Snapshot, exists bool) *discordgo.MessageEmbed {
+	link := "Original message was deleted"
+	if exists {
+		link = fmt.Sprintf("[Click here desu~](%v)", entry.Original.Link(guild.ID))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Description: snapshot.Content,
+		Color:       int(guild.EmbedColour),
+		Author: &discordgo.MessageEmbedAuthor{
+			Name:    snapshot.AuthorName,
+			IconURL: snapshot.AuthorAvatar,
+		},
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Original message", Value: link, Inline: true},
+			{Name: "Starboard", Value: fmt.Sprintf("[Click here desu~](%v)", entry.Starboard.Link(guild.ID)), Inline: true},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("%v stars | %v", entry.Stars, entry.CreatedAt.In(guild.Location()).Format("Jan 2, 2006")),
+		},
+		Timestamp: entry.CreatedAt.Format(time.RFC3339),
+	}
+
+	//Spoilered media is linked behind spoiler markup, an embedded image would reveal it.
+	for ind, uri := range snapshot.Attachments {
+		switch {
+		case snapshot.IsSpoiler(uri):
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+				Name:   fmt.Sprintf("Attachment %v", ind+1),
+				Value:  spoilerLink(uri, true),
+				Inline: true,
+			})
+		case embed.Image == nil && utils.ImageURLRegex.MatchString(uri):
+			embed.Image = &discordgo.MessageEmbedImage{URL: uri}
+		}
+	}
+
+	if embed.Description == "" && embed.Image == nil && len(snapshot.EmbedTitles) != 0 {
+		embed.Description = strings.Join(snapshot.EmbedTitles, "\n")
+	}
+
+	return embed
+}
@@ -24,6 +24,8 @@ func onReady(s *discordgo.Session, e *discordgo.Ready) {
 	if err != nil {
 		log.Warnln("Error adding guilds: ", err)
 	}
+
+	startJobs()
 }
 
 func trimPrefix(content, guildID string) string {
@@ -118,11 +120,35 @@ func messageCreated(s *discordgo.Session, m *discordgo.MessageCreate) {
 
 func reactCreated(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
 	if guild, ok := database.GuildCache[r.GuildID]; ok {
-		if !guild.Enabled || guild.StarboardChannel == "" {
+		if !guild.Enabled {
+			return
+		}
+
+		if r.ChannelID == guild.ApprovalChannel {
+			reviewReacted(s, guild, r)
 			return
 		}
 
 		if guild.ValidateEmoji(r.MessageReaction.Emoji) {
+			vote := database.NewVote(r.GuildID, r.ChannelID, r.MessageID, r.UserID, r.MessageReaction.Emoji.MessageFormat())
+			vote.Bot = r.UserID == s.State.User.ID || utils.IsBot(s, r.GuildID, r.UserID)
+
+			err := database.AddVote(vote)
+			if err != nil {
+				logrus.Warnf("reactCreated() -> database.AddVote(): %v", err)
+			}
+
+			//Banned voters' stars don't count, so there's nothing to update.
+			if guild.IsBannedVoter(r.UserID) {
+				if guild.RemoveBannedVotes {
+					err := s.MessageReactionRemove(r.ChannelID, r.MessageID, r.MessageReaction.Emoji.APIName(), r.UserID)
+					if err != nil {
+						logrus.Warnf("reactCreated() -> s.MessageReactionRemove(): %v", err)
+					}
+				}
+				return
+			}
+
 			if guild.IsBanned(r.ChannelID) {
 				return
 			}
@@ -134,7 +160,7 @@ func reactCreated(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
 			}
 
 			if msg.Author != nil {
-				if msg.Author.ID == s.State.User.ID {
+				if msg.Author.ID == s.State.User.ID || guild.WebhookByID(msg.WebhookID) != nil {
 					return
 				}
 
@@ -142,22 +168,26 @@ func reactCreated(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
 					return
 				}
 
-				for _, user := range guild.BlacklistedUsers {
-					if msg.Author.ID == user {
-						return
-					}
+				if guild.IsBlacklisted(msg.Author.ID) {
+					return
 				}
 			}
 
-			if react := FindReact(msg, guild.StarEmote); react != nil {
-				se, err := newStarboardEventAdd(s, r, msg, react)
+			for _, board := range guild.BoardsByEmoji(r.MessageReaction.Emoji) {
+				react := utils.FindReact(msg, board.StarEmote)
+				if react == nil {
+					continue
+				}
+
+				se, err := newStarboardEventAdd(s, r, msg, board, react)
 				if err != nil {
 					log.Warnln("newStarboardEventAdd(): ", err)
-					return
+					continue
 				}
 
-				if se.React.Count < guild.StarsRequired(se.message.ChannelID) {
-					return
+				//Weighted votes may reach the requirement with fewer reactions.
+				if len(guild.RoleWeights) == 0 && se.React.Count < guild.BoardStarsRequired(board, se.message.ChannelID) {
+					continue
 				}
 
 				p := database.NewPair(r.ChannelID, r.MessageID)
@@ -169,11 +199,16 @@ func reactCreated(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
 
 func reactRemoved(s *discordgo.Session, r *discordgo.MessageReactionRemove) {
 	if guild, ok := database.GuildCache[r.GuildID]; ok {
-		if !guild.Enabled || guild.StarboardChannel == "" {
+		if !guild.Enabled {
 			return
 		}
 
 		if guild.ValidateEmoji(r.MessageReaction.Emoji) {
+			err := database.RemoveVote(r.ChannelID, r.MessageID, r.UserID, r.MessageReaction.Emoji.MessageFormat())
+			if err != nil {
+				logrus.Warnf("reactRemoved() -> database.RemoveVote(): %v", err)
+			}
+
 			if guild.IsBanned(r.ChannelID) {
 				return
 			}
@@ -185,7 +220,7 @@ func reactRemoved(s *discordgo.Session, r *discordgo.MessageReactionRemove) {
 			}
 
 			if msg.Author != nil {
-				if msg.Author.ID == s.State.User.ID {
+				if msg.Author.ID == s.State.User.ID || guild.WebhookByID(msg.WebhookID) != nil {
 					return
 				}
 
@@ -193,44 +228,68 @@ func reactRemoved(s *discordgo.Session, r *discordgo.MessageReactionRemove) {
 					return
 				}
 
-				for _, user := range guild.BlacklistedUsers {
-					if msg.Author.ID == user {
-						return
-					}
+				if guild.IsBlacklisted(msg.Author.ID) {
+					return
 				}
 			}
 
-			se, err := newStarboardEventRemove(s, r, msg)
-			if err != nil {
-				log.Warnln("newStarboardEventRemove():", err)
-				return
+			for _, board := range guild.BoardsByEmoji(r.MessageReaction.Emoji) {
+				se, err := newStarboardEventRemove(s, r, msg, board)
+				if err != nil {
+					log.Warnln("newStarboardEventRemove():", err)
+					continue
+				}
+				p := database.NewPair(r.ChannelID, r.MessageID)
+				starboardQueue.Push(p, se)
 			}
-			p := database.NewPair(r.ChannelID, r.MessageID)
-			starboardQueue.Push(p, se)
 		}
 	}
 }
 
 func allReactsRemoved(s *discordgo.Session, r *discordgo.MessageReactionRemoveAll) {
 	guild, ok := database.GuildCache[r.GuildID]
+	if ok {
+		err := database.RemoveAllVotes(r.ChannelID, r.MessageID)
+		if err != nil {
+			logrus.Warnf("allReactsRemoved() -> database.RemoveAllVotes(): %v", err)
+		}
+	}
+
 	msg, err := s.ChannelMessage(r.ChannelID, r.MessageID)
 	if err != nil {
 		logrus.Warnf("allReactsRemoved() -> s.ChannelMessage(): %v. Channel ID: %v, Message ID: %v", err, r.ChannelID, r.MessageID)
 		return
 	}
 
-	if ok && guild.Enabled && guild.StarboardChannel != "" && !guild.IsBanned(r.ChannelID) && msg.Author.ID != s.State.User.ID {
-		repost, err := database.Repost(r.ChannelID, r.MessageID)
+	if ok && guild.Enabled && len(guild.ActiveBoards()) != 0 && !guild.IsBanned(r.ChannelID) && msg.Author.ID != s.State.User.ID && guild.WebhookByID(msg.WebhookID) == nil {
+		reposts, err := database.Reposts(r.ChannelID, r.MessageID)
 		if err != nil {
 			log.Warn(err)
 		}
 
-		if repost != nil {
+		for _, repost := range reposts {
+			if repost.Frozen || repost.Forced || guild.RemovalThreshold(r.ChannelID).Mode == database.RemovalNever {
+				continue
+			}
+
 			log.Infof("Removing starboard (all reactions removed) %v in channel %v", repost.Starboard.MessageID, repost.Starboard.ChannelID)
-			err := s.ChannelMessageDelete(repost.Starboard.ChannelID, repost.Starboard.MessageID)
+			err := deleteStarboardMessage(s, guild, repost)
 			if err != nil {
-				log.Warnln("allReactsRemoved() -> s.ChannelMessageDelete(): ", err)
+				log.Warnln("allReactsRemoved() -> deleteStarboardMessage(): ", err)
 			}
+
+			err = database.DeleteMessage(repost.Original, repost.Board)
+			if err != nil {
+				log.Warnln("allReactsRemoved() -> database.DeleteMessage(): ", err)
+			}
+
+			entry := database.NewAuditEntry(guild.ID, database.AuditRemove, "")
+			entry.Board = repost.Board
+			entry.Original = repost.Original
+			entry.Starboard = repost.Starboard
+			entry.Before = repost.Stars
+			entry.Details = "All reactions were removed"
+			utils.Audit(s, entry)
 		}
 	}
 }
@@ -240,7 +299,7 @@ func messageDeleted(s *discordgo.Session, m *discordgo.MessageDelete) {
 		guild, ok = database.GuildCache[m.GuildID]
 	)
 
-	if ok && guild.Enabled && guild.StarboardChannel != "" && !guild.IsBanned(m.ChannelID) {
+	if ok && guild.Enabled && len(guild.ActiveBoards()) != 0 && !guild.IsBanned(m.ChannelID) {
 		se, err := newStarboardEventDeleted(s, m)
 		if err != nil {
 			log.Warnln("newStarboardEventDeleted(): ", err)
@@ -251,6 +310,47 @@ func messageDeleted(s *discordgo.Session, m *discordgo.MessageDelete) {
 	}
 }
 
+func messageUpdated(s *discordgo.Session, m *discordgo.MessageUpdate) {
+	guild, ok := database.GuildCache[m.GuildID]
+	if !ok || !guild.Enabled || guild.IsBanned(m.ChannelID) {
+		return
+	}
+
+	boards := make([]*database.Board, 0)
+	for _, board := range guild.ActiveBoards() {
+		repost, err := database.Repost(m.ChannelID, m.ID, board.Name)
+		if err != nil {
+			log.Warnln("messageUpdated() -> database.Repost(): ", err)
+			return
+		}
+
+		if repost != nil {
+			boards = append(boards, board)
+		}
+	}
+
+	if len(boards) == 0 {
+		return
+	}
+
+	msg, err := s.ChannelMessage(m.ChannelID, m.ID)
+	if err != nil {
+		logrus.Warnf("messageUpdated() -> getMessage(): %v. Channel ID: %v, Message ID: %v", err, m.ChannelID, m.ID)
+		return
+	}
+
+	for _, board := range boards {
+		se, err := newStarboardEventUpdated(s, m, msg, board)
+		if err != nil {
+			log.Warnln("newStarboardEventUpdated(): ", err)
+			continue
+		}
+
+		p := database.NewPair(m.ChannelID, m.ID)
+		starboardQueue.Push(p, se)
+	}
+}
+
 func guildCreated(s *discordgo.Session, g *discordgo.GuildCreate) {
 	if len(database.GuildCache) == 0 {
 		return
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//Audited actions.
+const (
+	AuditCreate      = "create"
+	AuditEdit        = "edit"
+	AuditRemove      = "remove"
+	AuditDelete      = "delete"
+	AuditStar        = "star"
+	AuditUnstar      = "unstar"
+	AuditFreeze      = "freeze"
+	AuditUnfreeze    = "unfreeze"
+	AuditPromote     = "promote"
+	AuditDemote      = "demote"
+	AuditSettings    = "settings"
+	AuditSetup       = "setup"
+	AuditBan         = "ban"
+	AuditUnban       = "unban"
+	AuditBlacklist   = "blacklist"
+	AuditUnblacklist = "unblacklist"
+)
+
+//AuditEntry is a record of a starboard action or a settings change. UserID is empty for actions Eugen took on its own.
+type AuditEntry struct {
+	GuildID   string       `bson:"guild_id" json:"guild_id"`
+	Action    string       `bson:"action" json:"action"`
+	UserID    string       `bson:"user_id" json:"user_id"`
+	Board     string       `bson:"board" json:"board"`
+	Original  *MessagePair `bson:"original,omitempty" json:"original,omitempty"`
+	Starboard *MessagePair `bson:"starboard,omitempty" json:"starboard,omitempty"`
+	Before    int          `bson:"before" json:"before"`
+	After     int          `bson:"after" json:"after"`
+	Details   string       `bson:"details" json:"details"`
+	CreatedAt time.Time    `bson:"created_at" json:"created_at"`
+}
+
+func NewAuditEntry(guildID, action, userID string) *AuditEntry {
+	return &AuditEntry{
+		GuildID:   guildID,
+		Action:    action,
+		UserID:    userID,
+		CreatedAt: time.Now(),
+	}
+}
+
+//IsEntryAction checks if an audit entry is about a starboard entry rather than settings.
+func (a *AuditEntry) IsEntryAction() bool {
+	return a.Original != nil
+}
+
+func InsertAudit(entry *AuditEntry) error {
+	col := DB.Collection("audit")
+	_, err := col.InsertOne(context.Background(), entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+//AuditLog returns latest audit entries of a guild, optionally only entries of an action or triggered by a user.
+func AuditLog(guildID, action, userID string, limit int64) ([]*AuditEntry, error) {
+	filter := bson.M{"guild_id": guildID}
+	if action != "" {
+		filter["action"] = action
+	}
+
+	if userID != "" {
+		filter["user_id"] = userID
+	}
+
+	col := DB.Collection("audit")
+	cur, err := col.Find(context.Background(), filter, options.Find().SetSort(bson.M{"created_at": -1}).SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*AuditEntry, 0)
+	err = cur.All(context.Background(), &entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,221 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//DefaultBoardName is a display name of a board built from top-level guild settings. Default board is stored with an empty name.
+const DefaultBoardName = "default"
+
+//Board is a named starboard with its own channel, emote and requirements.
+type Board struct {
+	Name         string `json:"name" bson:"name"`
+	Channel      string `json:"channel" bson:"channel"`
+	NSFWChannel  string `json:"nsfw_channel" bson:"nsfw_channel"`
+	StarEmote    string `json:"emote" bson:"emote"`
+	MinimumStars int    `json:"stars" bson:"stars"`
+	Selfstar     bool   `json:"selfstar" bson:"selfstar"`
+	NSFW         bool   `json:"nsfw" bson:"nsfw"`
+	Webhook      bool   `json:"webhook" bson:"webhook"`
+}
+
+func NewBoard(name, channelID, emote string, stars int) *Board {
+	return &Board{
+		Name:         name,
+		Channel:      channelID,
+		StarEmote:    emote,
+		MinimumStars: stars,
+		Selfstar:     true,
+		NSFW:         false,
+	}
+}
+
+func (b *Board) IsDefault() bool {
+	return b.Name == ""
+}
+
+func (b *Board) DisplayName() string {
+	if b.IsDefault() {
+		return DefaultBoardName
+	}
+	return b.Name
+}
+
+func (b *Board) ValidateEmoji(emoji discordgo.Emoji) bool {
+	return strings.EqualFold(b.StarEmote, emoji.MessageFormat())
+}
+
+func (b *Board) IsGuildEmoji() bool {
+	return strings.HasPrefix(b.StarEmote, "<:")
+}
+
+//Destination returns a channel ID the board posts messages from a channel to.
+func (b *Board) Destination(nsfw bool) string {
+	if nsfw && b.NSFWChannel != "" {
+		return b.NSFWChannel
+	}
+	return b.Channel
+}
+
+//DefaultBoard returns a board built from guild's top-level starboard settings. Unlike new boards it accepts NSFW messages, as the single starboard always did.
+func (g *Guild) DefaultBoard() *Board {
+	return &Board{
+		Name:         "",
+		Channel:      g.StarboardChannel,
+		NSFWChannel:  g.NSFWStarboardChannel,
+		StarEmote:    g.StarEmote,
+		MinimumStars: g.MinimumStars,
+		Selfstar:     g.Selfstar,
+		NSFW:         true,
+		Webhook:      g.Webhook,
+	}
+}
+
+//AllBoards returns the default board followed by all named boards.
+func (g *Guild) AllBoards() []*Board {
+	return append([]*Board{g.DefaultBoard()}, g.Boards...)
+}
+
+//ActiveBoards returns boards that have a starboard channel set.
+func (g *Guild) ActiveBoards() []*Board {
+	boards := make([]*Board, 0)
+	for _, b := range g.AllBoards() {
+		if b.Channel != "" {
+			boards = append(boards, b)
+		}
+	}
+	return boards
+}
+
+//BoardsByEmoji returns active boards that use given emoji.
+func (g *Guild) BoardsByEmoji(emoji discordgo.Emoji) []*Board {
+	boards := make([]*Board, 0)
+	for _, b := range g.ActiveBoards() {
+		if b.ValidateEmoji(emoji) {
+			boards = append(boards, b)
+		}
+	}
+	return boards
+}
+
+//FindBoard returns a board by its name. Both empty string and "default" return the default board.
+func (g *Guild) FindBoard(name string) *Board {
+	if name == "" || strings.EqualFold(name, DefaultBoardName) {
+		return g.DefaultBoard()
+	}
+
+	for _, b := range g.Boards {
+		if strings.EqualFold(b.Name, name) {
+			return b
+		}
+	}
+	return nil
+}
+
+//BoardStarsRequired returns a star requirement of a board in a channel. Per channel requirements only apply to the default board.
+func (g *Guild) BoardStarsRequired(b *Board, channelID string) int {
+	if b.IsDefault() {
+		return g.StarsRequired(channelID)
+	}
+	return b.MinimumStars
+}
+
+func (g *Guild) BoardsToString() string {
+	if len(g.Boards) == 0 {
+		return "none"
+	}
+
+	var sb strings.Builder
+	for _, b := range g.Boards {
+		sb.WriteString(fmt.Sprintf("**%v**: %v <#%v> | %v stars\n", b.Name, b.StarEmote, b.Channel, b.MinimumStars))
+	}
+
+	return sb.String()
+}
+
+func AddBoard(guildID string, board *Board) error {
+	col := DB.Collection("guilds")
+
+	res := col.FindOneAndUpdate(context.Background(), bson.M{
+		"guild_id": guildID,
+	}, bson.M{
+		"$set": bson.M{
+			"updated_at": time.Now(),
+		},
+		"$push": bson.M{
+			"boards": board,
+		},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.After))
+
+	guild := &Guild{}
+	err := res.Decode(guild)
+	if err != nil {
+		return err
+	}
+
+	GuildCache[guildID] = guild
+	return nil
+}
+
+//RemoveBoard removes a board from guild's settings and deletes its starboard entries. Returns the number of deleted entries.
+func RemoveBoard(guildID, name string) (int64, error) {
+	col := DB.Collection("guilds")
+
+	res := col.FindOneAndUpdate(context.Background(), bson.M{
+		"guild_id": guildID,
+	}, bson.M{
+		"$set": bson.M{
+			"updated_at": time.Now(),
+		},
+		"$pull": bson.M{
+			"boards": bson.M{"name": name},
+		},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.After))
+
+	guild := &Guild{}
+	err := res.Decode(guild)
+	if err != nil {
+		return 0, err
+	}
+
+	GuildCache[guildID] = guild
+
+	//Entries of a removed board would otherwise show up in leaderboards and exports, or be inherited by a new board with the same name.
+	deleted, err := DB.Collection("messages").DeleteMany(context.Background(), bson.M{"guild_id": guildID, "board": name})
+	if err != nil {
+		return 0, err
+	}
+
+	uncacheBoard(guildID, name)
+	return deleted.DeletedCount, nil
+}
+
+func SetBoardSetting(guildID, name, setting string, value interface{}) error {
+	col := DB.Collection("guilds")
+
+	res := col.FindOneAndUpdate(context.Background(), bson.M{
+		"guild_id":    guildID,
+		"boards.name": name,
+	}, bson.M{
+		"$set": bson.M{
+			"updated_at":          time.Now(),
+			"boards.$." + setting: value,
+		},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.After))
+
+	guild := &Guild{}
+	err := res.Decode(guild)
+	if err != nil {
+		return err
+	}
+
+	GuildCache[guildID] = guild
+	return nil
+}
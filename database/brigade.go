@@ -0,0 +1,154 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//BrigadeSettings configures detection of star raids. Zero values disable a check.
+type BrigadeSettings struct {
+	Stars      int     `json:"stars" bson:"stars"`
+	Window     int     `json:"window" bson:"window"`
+	NewDays    int     `json:"new_days" bson:"new_days"`
+	Ratio      float64 `json:"ratio" bson:"ratio"`
+	LogChannel string  `json:"log_channel" bson:"log_channel"`
+}
+
+//DefaultBrigadeSettings flags 10 stars within 5 minutes or over a half of stars from accounts or members younger than a week.
+func DefaultBrigadeSettings() *BrigadeSettings {
+	return &BrigadeSettings{
+		Stars:   10,
+		Window:  5,
+		NewDays: 7,
+		Ratio:   0.5,
+	}
+}
+
+func (b *BrigadeSettings) String() string {
+	surge := "off"
+	if b.Stars > 0 {
+		surge = fmt.Sprintf("%v stars in %v minutes", b.Stars, b.Window)
+	}
+
+	newVoters := "off"
+	if b.NewDays > 0 && b.Ratio > 0 {
+		newVoters = fmt.Sprintf("%v%% of voters younger than %v days", b.Ratio*100, b.NewDays)
+	}
+
+	logChannel := "none"
+	if b.LogChannel != "" {
+		logChannel = fmt.Sprintf("<#%v>", b.LogChannel)
+	}
+
+	return fmt.Sprintf("**Surge:** %v | **New voters:** %v | **Log:** %v", surge, newVoters, logChannel)
+}
+
+//Flag is a message held off a board because its stars look like a raid. Cleared flags were reviewed by moderators and don't hold the message anymore.
+type Flag struct {
+	GuildID   string       `bson:"guild_id" json:"guild_id"`
+	Board     string       `bson:"board" json:"board"`
+	Original  *MessagePair `bson:"original" json:"original"`
+	AuthorID  string       `bson:"author_id" json:"author_id"`
+	Reason    string       `bson:"reason" json:"reason"`
+	Voters    []string     `bson:"voters" json:"voters"`
+	Stars     int          `bson:"stars" json:"stars"`
+	Cleared   bool         `bson:"cleared" json:"cleared"`
+	CreatedAt time.Time    `bson:"created_at" json:"created_at"`
+}
+
+func NewFlag(original *MessagePair, guildID, board string) *Flag {
+	return &Flag{
+		GuildID:   guildID,
+		Board:     board,
+		Original:  original,
+		Voters:    make([]string, 0),
+		CreatedAt: time.Now(),
+	}
+}
+
+func flagFilter(pair *MessagePair, board string) bson.M {
+	return bson.M{
+		"original.channel_id": pair.ChannelID,
+		"original.message_id": pair.MessageID,
+		"board":               boardFilter(board),
+	}
+}
+
+func InsertFlag(flag *Flag) error {
+	col := DB.Collection("flagged")
+	_, err := col.InsertOne(context.Background(), flag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+//FindFlag returns a flag of a message on a board or nil if it wasn't flagged.
+func FindFlag(pair *MessagePair, board string) (*Flag, error) {
+	col := DB.Collection("flagged")
+	res := col.FindOne(context.Background(), flagFilter(pair, board))
+
+	flag := &Flag{}
+	err := res.Decode(flag)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return flag, nil
+}
+
+//ClearFlag releases a held message, so it's starboarded as usual.
+func ClearFlag(pair *MessagePair, board string) error {
+	col := DB.Collection("flagged")
+	res, err := col.UpdateOne(context.Background(), flagFilter(pair, board), bson.M{
+		"$set": bson.M{"cleared": true},
+	})
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("message is not flagged")
+	}
+
+	return nil
+}
+
+func DeleteFlag(pair *MessagePair, board string) error {
+	col := DB.Collection("flagged")
+	_, err := col.DeleteOne(context.Background(), flagFilter(pair, board))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+//HeldMessages returns flags of a guild that still hold messages, newest first.
+func HeldMessages(guildID string) ([]*Flag, error) {
+	col := DB.Collection("flagged")
+	cur, err := col.Find(context.Background(), bson.M{
+		"guild_id": guildID,
+		"cleared":  false,
+	}, options.Find().SetSort(bson.M{"created_at": -1}))
+	if err != nil {
+		return nil, err
+	}
+
+	flags := make([]*Flag, 0)
+	err = cur.All(context.Background(), &flags)
+	if err != nil {
+		return nil, err
+	}
+
+	return flags, nil
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"errors"
 	"log"
 	"os"
 	"time"
@@ -18,10 +19,11 @@ var (
 	Client *mongo.Client
 )
 
-func init() {
+//Connect connects to Mongo DB at MONGODB_URL. It's called by the main package before anything touches the database.
+func Connect() error {
 	connStr := os.Getenv("MONGODB_URL")
 	if connStr == "" {
-		log.Fatalln("MONGODB_URL env variable is not found.")
+		return errors.New("MONGODB_URL env variable is not found")
 	}
 
 	var err error
@@ -30,8 +32,15 @@ func init() {
 
 	Client, err = mongo.Connect(ctx, options.Client().ApplyURI(connStr))
 	if err != nil {
-		log.Fatalln("Error connecting to Mongo DB", err)
+		return err
 	}
 
 	DB = Client.Database("eugen")
+
+	err = ensureSearchIndex(ctx)
+	if err != nil {
+		log.Println("Error creating search index", err)
+	}
+
+	return nil
 }
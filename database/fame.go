@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+//FameMultiplier is used to calculate hall of fame requirement from board's requirement if guild didn't set one.
+const FameMultiplier = 3
+
+//FameStarsRequired returns stars board's entries need to be promoted to the hall of fame.
+func (g *Guild) FameStarsRequired(b *Board, channelID string) int {
+	if g.FameStars > 0 {
+		return g.FameStars
+	}
+	return g.BoardStarsRequired(b, channelID) * FameMultiplier
+}
+
+//SetMessageFame sets a hall of fame post of a starboard entry and marks it as promoted, so it's never promoted automatically again. Nil demotes the entry.
+func SetMessageFame(pair *MessagePair, board string, fame *MessagePair) error {
+	collection := DB.Collection("messages")
+	_, err := collection.UpdateOne(context.Background(), bson.M{"original.channel_id": pair.ChannelID, "original.message_id": pair.MessageID, "board": boardFilter(board)}, bson.M{
+		"$set": bson.M{"fame": fame, "promoted": true},
+	})
+	if err != nil {
+		return err
+	}
+
+	uncacheMessage(pair, board)
+	return nil
+}
+
+//RepostByFame returns a starboard entry by its hall of fame post.
+func RepostByFame(channelID, id string) (*Message, error) {
+	collection := DB.Collection("messages")
+	res := collection.FindOne(context.Background(), bson.M{"fame.channel_id": channelID, "fame.message_id": id})
+	if err := res.Err(); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	m := &Message{}
+	err := res.Decode(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 	"time"
 
@@ -28,39 +29,112 @@ type Guild struct {
 	ChannelSettings      []*ChannelSettings `json:"channel_settings" bson:"channel_settings"`
 	BlacklistedUsers     []string           `json:"blacklisted_users" bson:"blacklisted_users"`
 	BannedChannels       []string           `json:"banned" bson:"banned"`
+	Boards               []*Board           `json:"boards" bson:"boards"`
+	Removal              *RemovalThreshold  `json:"removal" bson:"removal"`
+	AutoRepair           bool               `json:"autorepair" bson:"autorepair"`
+	Webhook              bool               `json:"webhook" bson:"webhook"`
+	Webhooks             []*Webhook         `json:"webhooks" bson:"webhooks"`
+	Spoilers             string             `json:"spoilers" bson:"spoilers"`
+	Routes               []*RouteRule       `json:"routes" bson:"routes"`
+	VoterRoles           []string           `json:"voter_roles" bson:"voter_roles"`
+	RoleWeights          []*RoleWeight      `json:"role_weights" bson:"role_weights"`
+	BannedVoters         []string           `json:"banned_voters" bson:"banned_voters"`
+	RemoveBannedVotes    bool               `json:"removebanned" bson:"removebanned"`
+	MinAccountAge        int                `json:"accountage" bson:"accountage"`
+	Brigade              *BrigadeSettings   `json:"brigade" bson:"brigade"`
+	ApprovalChannel      string             `json:"approval" bson:"approval"`
+	AuditChannel         string             `json:"audit" bson:"audit"`
+	Timezone             string             `json:"timezone" bson:"timezone"`
+	HallOfFame           string             `json:"halloffame" bson:"halloffame"`
+	FameStars            int                `json:"famestars" bson:"famestars"`
 	CreatedAt            time.Time          `json:"created_at" bson:"created_at"`
 	UpdatedAt            time.Time          `json:"updated_at" bson:"updated_at"`
 }
 
+const (
+	//SpoilerBlur posts spoilered media as spoilered files. It's the default behaviour.
+	SpoilerBlur = "blur"
+	//SpoilerSkip doesn't starboard messages with spoilers at all.
+	SpoilerSkip = "skip"
+)
+
 type ChannelSettings struct {
-	ID              string `json:"id" bson:"id"`
-	StarRequirement int    `json:"star_requirement" bson:"star_requirement"`
+	ID              string            `json:"id" bson:"id"`
+	StarRequirement int               `json:"star_requirement" bson:"star_requirement"`
+	Removal         *RemovalThreshold `json:"removal" bson:"removal"`
+}
+
+func (ch *ChannelSettings) String() string {
+	str := "default"
+	if ch.StarRequirement > 0 {
+		str = strconv.Itoa(ch.StarRequirement)
+	}
+
+	if ch.Removal != nil {
+		str += fmt.Sprintf(" (removal: %v)", ch.Removal)
+	}
+
+	return str
 }
 
+//SpoilerMode returns how starboard handles spoilered messages.
+func (g *Guild) SpoilerMode() string {
+	if g.Spoilers == "" {
+		return SpoilerBlur
+	}
+	return g.Spoilers
+}
+
+//Location returns guild's timezone, UTC if it's not set or unknown.
+func (g *Guild) Location() *time.Location {
+	loc, err := LoadTimezone(g.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+
+//StarsRequired returns a star requirement of a channel. Channel settings with zero requirement inherit guild's minimum stars.
 func (g *Guild) StarsRequired(channelID string) int {
 	for _, ch := range g.ChannelSettings {
-		if ch.ID == channelID {
+		if ch.ID == channelID && ch.StarRequirement > 0 {
 			return ch.StarRequirement
 		}
 	}
 	return g.MinimumStars
 }
 
+//RemovalThreshold returns a removal threshold of a channel, falling back to guild's and then to the default one.
+func (g *Guild) RemovalThreshold(channelID string) *RemovalThreshold {
+	for _, ch := range g.ChannelSettings {
+		if ch.ID == channelID && ch.Removal != nil {
+			return ch.Removal
+		}
+	}
+
+	if g.Removal != nil {
+		return g.Removal
+	}
+
+	return DefaultRemovalThreshold()
+}
+
 func (g *Guild) ChannelSettingsToString() string {
 	var sb strings.Builder
 	if len(g.ChannelSettings) == 0 {
 		return "none"
 	}
 
-	sb.WriteString(fmt.Sprintf("<#%v>``%v``: %v ", g.ChannelSettings[0].ID, g.ChannelSettings[0].ID, g.ChannelSettings[0].StarRequirement))
+	sb.WriteString(fmt.Sprintf("<#%v>``%v``: %v ", g.ChannelSettings[0].ID, g.ChannelSettings[0].ID, g.ChannelSettings[0]))
 	inRow := 1
 	if len(g.ChannelSettings) > 1 {
 		for _, ch := range g.ChannelSettings[1:] {
 			if inRow == 2 {
-				sb.WriteString(fmt.Sprintf("\n<#%v>``%v``: %v ", ch.ID, ch.ID, ch.StarRequirement))
+				sb.WriteString(fmt.Sprintf("\n<#%v>``%v``: %v ", ch.ID, ch.ID, ch))
 				inRow = 0
 			} else {
-				sb.WriteString(fmt.Sprintf("| <#%v>``%v``: %v ", ch.ID, ch.ID, ch.StarRequirement))
+				sb.WriteString(fmt.Sprintf("| <#%v>``%v``: %v ", ch.ID, ch.ID, ch))
 			}
 			inRow++
 		}
@@ -109,6 +183,16 @@ func (g *Guild) BlacklistedToString() string {
 	return sb.String()
 }
 
+//IsBlacklisted checks if user's messages can't be starboarded.
+func (g *Guild) IsBlacklisted(userID string) bool {
+	for _, id := range g.BlacklistedUsers {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
 func (g *Guild) IsBanned(channelID string) bool {
 	for _, id := range g.BannedChannels {
 		if id == channelID {
@@ -118,8 +202,9 @@ func (g *Guild) IsBanned(channelID string) bool {
 	return false
 }
 
+//ValidateEmoji checks if emoji is used by any of guild's active boards.
 func (g *Guild) ValidateEmoji(emoji discordgo.Emoji) bool {
-	return strings.EqualFold(g.StarEmote, emoji.MessageFormat())
+	return len(g.BoardsByEmoji(emoji)) != 0
 }
 
 func (g *Guild) IsGuildEmoji() bool {
@@ -142,6 +227,12 @@ func NewGuild(guildName, guildID string) *Guild {
 		BlacklistedUsers:     make([]string, 0),
 		ChannelSettings:      make([]*ChannelSettings, 0),
 		BannedChannels:       make([]string, 0),
+		Boards:               make([]*Board, 0),
+		Webhooks:             make([]*Webhook, 0),
+		Routes:               make([]*RouteRule, 0),
+		VoterRoles:           make([]string, 0),
+		RoleWeights:          make([]*RoleWeight, 0),
+		BannedVoters:         make([]string, 0),
 		CreatedAt:            time.Now(),
 		UpdatedAt:            time.Now(),
 	}
@@ -308,7 +399,7 @@ func SetStarRequirement(guildID, channelID string, stars int) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
 	defer cancel()
 
-	cs := &ChannelSettings{channelID, stars}
+	cs := &ChannelSettings{ID: channelID, StarRequirement: stars}
 	res := col.FindOneAndUpdate(ctx, bson.M{
 		"guild_id":            guildID,
 		"channel_settings.id": channelID,
@@ -373,3 +464,50 @@ func UnsetStarRequirement(guildID, channelID string) error {
 	GuildCache[guildID] = guild
 	return nil
 }
+
+func SetRemovalThreshold(guildID, channelID string, removal *RemovalThreshold) error {
+	col := DB.Collection("guilds")
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	cs := &ChannelSettings{ID: channelID, StarRequirement: 0, Removal: removal}
+	res := col.FindOneAndUpdate(ctx, bson.M{
+		"guild_id":            guildID,
+		"channel_settings.id": channelID,
+	}, bson.M{
+		"$set": bson.M{
+			"updated_at":                 time.Now(),
+			"channel_settings.$.removal": removal,
+		},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.After))
+
+	if err := res.Err(); err != nil {
+		if err == mongo.ErrNoDocuments {
+			res = col.FindOneAndUpdate(ctx, bson.M{
+				"guild_id": guildID,
+			}, bson.M{
+				"$set": bson.M{
+					"updated_at": time.Now(),
+				},
+				"$addToSet": bson.M{
+					"channel_settings": cs,
+				},
+			}, options.FindOneAndUpdate().SetReturnDocument(options.After))
+			if err := res.Err(); err != nil {
+				if err != mongo.ErrNoDocuments {
+					return err
+				}
+			}
+		} else {
+			return err
+		}
+	}
+
+	guild := &Guild{}
+	err := res.Decode(guild)
+	if err != nil {
+		return err
+	}
+	GuildCache[guildID] = guild
+	return nil
+}
@@ -0,0 +1,133 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//LeaderboardEntry is an aggregated star count of a user or a channel.
+type LeaderboardEntry struct {
+	ID      string `bson:"_id" json:"id"`
+	Stars   int    `bson:"stars" json:"stars"`
+	Entries int    `bson:"entries" json:"entries"`
+}
+
+//LeaderboardFilter narrows starboard entries used for leaderboards.
+type LeaderboardFilter struct {
+	GuildID string
+	Since   time.Time
+	//Board is a name of a board. All boards are used if nil.
+	Board *string
+}
+
+func (f *LeaderboardFilter) query() bson.M {
+	query := bson.M{"guild_id": f.GuildID}
+	if !f.Since.IsZero() {
+		query["created_at"] = bson.M{"$gte": f.Since}
+	}
+	if f.Board != nil {
+		query["board"] = boardFilter(*f.Board)
+	}
+
+	return query
+}
+
+//TopUsers returns authors with the most stars on their starboard entries.
+func TopUsers(filter *LeaderboardFilter, limit int) ([]*LeaderboardEntry, error) {
+	return topBy(filter, "$author_id", limit)
+}
+
+//TopChannels returns channels with the most stars on their starboard entries.
+func TopChannels(filter *LeaderboardFilter, limit int) ([]*LeaderboardEntry, error) {
+	return topBy(filter, "$original.channel_id", limit)
+}
+
+func topBy(filter *LeaderboardFilter, field string, limit int) ([]*LeaderboardEntry, error) {
+	col := DB.Collection("messages")
+	match := filter.query()
+	if field == "$author_id" {
+		match["author_id"] = bson.M{"$nin": bson.A{"", nil}}
+	}
+
+	cur, err := col.Aggregate(context.Background(), bson.A{
+		bson.M{"$match": match},
+		bson.M{"$group": bson.M{
+			"_id":     field,
+			"stars":   bson.M{"$sum": "$stars"},
+			"entries": bson.M{"$sum": 1},
+		}},
+		bson.M{"$sort": bson.D{{Key: "stars", Value: -1}, {Key: "entries", Value: -1}}},
+		bson.M{"$limit": limit},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*LeaderboardEntry, 0)
+	err = cur.All(context.Background(), &entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+//TopMessages returns the most starred starboard entries.
+func TopMessages(filter *LeaderboardFilter, limit int) ([]*Message, error) {
+	col := DB.Collection("messages")
+	opts := options.Find().SetSort(bson.D{{Key: "stars", Value: -1}, {Key: "created_at", Value: 1}}).SetLimit(int64(limit))
+	cur, err := col.Find(context.Background(), filter.query(), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*Message, 0)
+	err = cur.All(context.Background(), &messages)
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+//RandomFilter narrows starboard entries picked at random. Empty fields match everything.
+type RandomFilter struct {
+	GuildID   string
+	ChannelID string
+	AuthorID  string
+	MinStars  int
+}
+
+//RandomMessages returns up to size random starboard entries using $sample.
+func RandomMessages(filter *RandomFilter, size int) ([]*Message, error) {
+	match := bson.M{"guild_id": filter.GuildID}
+	if filter.ChannelID != "" {
+		match["original.channel_id"] = filter.ChannelID
+	}
+	if filter.AuthorID != "" {
+		match["author_id"] = filter.AuthorID
+	}
+	if filter.MinStars > 0 {
+		match["stars"] = bson.M{"$gte": filter.MinStars}
+	}
+
+	col := DB.Collection("messages")
+	cur, err := col.Aggregate(context.Background(), bson.A{
+		bson.M{"$match": match},
+		bson.M{"$sample": bson.M{"size": size}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*Message, 0)
+	err = cur.All(context.Background(), &messages)
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
@@ -2,31 +2,80 @@ package database
 
 import (
 	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
 	"time"
 
-	"github.com/jasonlvhit/gocron"
+	"github.com/bwmarrin/discordgo"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var (
-	messageCache = make(map[MessagePair]Message)
+	messageCache   = make(map[messageKey]Message)
+	messageCacheMu sync.RWMutex
 )
 
-func init() {
-	go func() {
-		s := gocron.NewScheduler()
-		s.Every(10).Hours().Do(func() {
-			messageCache = make(map[MessagePair]Message)
-		})
-		<-s.Start()
-	}()
+//ResetMessageCache drops cached starboard entries. It's run periodically by the scheduler to keep the cache small.
+func ResetMessageCache() {
+	messageCacheMu.Lock()
+	messageCache = make(map[messageKey]Message)
+	messageCacheMu.Unlock()
+}
+
+func cachedMessage(key messageKey) (Message, bool) {
+	messageCacheMu.RLock()
+	defer messageCacheMu.RUnlock()
+
+	m, ok := messageCache[key]
+	return m, ok
+}
+
+func cacheMessage(m Message) {
+	messageCacheMu.Lock()
+	messageCache[newKey(*m.Original, m.Board)] = m
+	messageCacheMu.Unlock()
+}
+
+func uncacheMessage(pair *MessagePair, board string) {
+	messageCacheMu.Lock()
+	delete(messageCache, newKey(*pair, board))
+	messageCacheMu.Unlock()
+}
+
+func uncacheBoard(guildID, board string) {
+	messageCacheMu.Lock()
+	for key, m := range messageCache {
+		if key.Board == board && m.GuildID == guildID {
+			delete(messageCache, key)
+		}
+	}
+	messageCacheMu.Unlock()
+}
+
+//messageKey identifies a starboard entry of an original message on one of guild's boards.
+type messageKey struct {
+	Pair  MessagePair
+	Board string
 }
 
 type Message struct {
 	GuildID   string       `bson:"guild_id" json:"guild_id"`
+	Board     string       `bson:"board" json:"board"`
+	AuthorID  string       `bson:"author_id" json:"author_id"`
+	Stars     int          `bson:"stars" json:"stars"`
+	Frozen    bool         `bson:"frozen" json:"frozen"`
+	Forced    bool         `bson:"forced" json:"forced"`
+	Snapshot  *Snapshot    `bson:"snapshot" json:"snapshot"`
 	Original  *MessagePair `bson:"original" json:"original"`
 	Starboard *MessagePair `bson:"starboard" json:"starboard"`
+	WebhookID string       `bson:"webhook_id" json:"webhook_id"`
+	Fame      *MessagePair `bson:"fame" json:"fame"`
+	Promoted  bool         `bson:"promoted" json:"promoted"`
 	CreatedAt time.Time    `bson:"created_at" json:"created_at"`
 }
 
@@ -35,13 +84,115 @@ type MessagePair struct {
 	MessageID string `bson:"message_id" json:"message_id"`
 }
 
+//Snapshot is a copy of an original message taken when it was starboarded, so deleted originals can still be shown.
+type Snapshot struct {
+	AuthorName   string   `bson:"author_name" json:"author_name"`
+	AuthorAvatar string   `bson:"author_avatar" json:"author_avatar"`
+	Content      string   `bson:"content" json:"content"`
+	Attachments  []string `bson:"attachments" json:"attachments"`
+	EmbedTitles  []string `bson:"embed_titles" json:"embed_titles"`
+	//Spoilers are attachments and embedded images hidden behind spoilers in the original message.
+	Spoilers []string `bson:"spoilers" json:"spoilers"`
+}
+
+//spoilerRegex matches spoiler markup.
+var spoilerRegex = regexp.MustCompile(`(?s)\|\|(.+?)\|\|`)
+
+func NewSnapshot(msg *discordgo.Message) *Snapshot {
+	snapshot := &Snapshot{
+		Content:     msg.Content,
+		Attachments: make([]string, 0),
+		EmbedTitles: make([]string, 0),
+		Spoilers:    make([]string, 0),
+	}
+
+	if msg.Author != nil {
+		snapshot.AuthorName = msg.Author.String()
+		snapshot.AuthorAvatar = msg.Author.AvatarURL("")
+	}
+
+	for _, a := range msg.Attachments {
+		snapshot.Attachments = append(snapshot.Attachments, a.URL)
+		if strings.HasPrefix(a.Filename, "SPOILER_") {
+			snapshot.Spoilers = append(snapshot.Spoilers, a.URL)
+		}
+	}
+
+	spoilers := spoilerRegex.FindAllStringSubmatch(msg.Content, -1)
+	for _, embed := range msg.Embeds {
+		if embed.Title != "" {
+			snapshot.EmbedTitles = append(snapshot.EmbedTitles, embed.Title)
+		}
+		if embed.Image != nil && embed.Image.URL != "" {
+			snapshot.Attachments = append(snapshot.Attachments, embed.Image.URL)
+			for _, spoiler := range spoilers {
+				if embed.URL != "" && strings.Contains(spoiler[1], embed.URL) {
+					snapshot.Spoilers = append(snapshot.Spoilers, embed.Image.URL)
+					break
+				}
+			}
+		}
+	}
+
+	return snapshot
+}
+
+//Equal checks if two snapshots have the same content, attachments and embeds. Author is ignored.
+func (s *Snapshot) Equal(other *Snapshot) bool {
+	return s.Content == other.Content &&
+		equalStrings(s.Attachments, other.Attachments) &&
+		equalStrings(s.EmbedTitles, other.EmbedTitles) &&
+		equalStrings(s.Spoilers, other.Spoilers)
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+//HasSpoiler checks if a snapshotted message had spoilered attachments or spoiler markup in its content.
+func (s *Snapshot) HasSpoiler() bool {
+	for _, uri := range s.Attachments {
+		if s.IsSpoiler(uri) {
+			return true
+		}
+	}
+
+	return spoilerRegex.MatchString(s.Content)
+}
+
+//IsSpoiler checks if a snapshotted attachment was spoilered. Snapshots taken before spoilers were recorded fall back to the attachment's filename.
+func (s *Snapshot) IsSpoiler(uri string) bool {
+	for _, spoiler := range s.Spoilers {
+		if spoiler == uri {
+			return true
+		}
+	}
+
+	return strings.HasPrefix(path.Base(uri), "SPOILER_")
+}
+
 func (p *MessagePair) String() string {
 	return p.ChannelID + " " + p.MessageID
 }
 
-func NewMessage(original, starboard *MessagePair, guildID string) *Message {
+//Link returns a jump link to the message.
+func (p *MessagePair) Link(guildID string) string {
+	return fmt.Sprintf("https://discord.com/channels/%v/%v/%v", guildID, p.ChannelID, p.MessageID)
+}
+
+func NewMessage(original, starboard *MessagePair, guildID, board string) *Message {
 	return &Message{
 		GuildID:   guildID,
+		Board:     board,
 		Original:  original,
 		Starboard: starboard,
 		CreatedAt: time.Now(),
@@ -55,6 +206,18 @@ func NewPair(channelID, messageID string) MessagePair {
 	}
 }
 
+func newKey(pair MessagePair, board string) messageKey {
+	return messageKey{Pair: pair, Board: board}
+}
+
+//boardFilter matches messages of a board. Messages of the default board created before named boards were introduced don't have a board field.
+func boardFilter(board string) interface{} {
+	if board == "" {
+		return bson.M{"$in": bson.A{"", nil}}
+	}
+	return board
+}
+
 func InsertOneMessage(post *Message) error {
 	collection := DB.Collection("messages")
 	_, err := collection.InsertOne(context.Background(), post)
@@ -62,7 +225,7 @@ func InsertOneMessage(post *Message) error {
 		return err
 	}
 
-	messageCache[NewPair(post.Original.ChannelID, post.Original.MessageID)] = *post
+	cacheMessage(*post)
 	return nil
 }
 
@@ -74,73 +237,164 @@ func InsertManyMessages(posts []interface{}) error {
 	}
 
 	for _, post := range posts {
-		pair := *post.(Message).Original
-		messageCache[NewPair(pair.ChannelID, pair.MessageID)] = post.(Message)
+		cacheMessage(post.(Message))
 	}
 	return nil
 }
 
-func DeleteMessage(pair *MessagePair) error {
+func DeleteMessage(pair *MessagePair, board string) error {
 	collection := DB.Collection("messages")
-	_, err := collection.DeleteOne(context.Background(), bson.D{{"original.channel_id", pair.ChannelID}, {"original.message_id", pair.MessageID}})
+	_, err := collection.DeleteOne(context.Background(), bson.M{"original.channel_id": pair.ChannelID, "original.message_id": pair.MessageID, "board": boardFilter(board)})
 	if err != nil {
 		return err
 	}
 
-	delete(messageCache, *pair)
+	uncacheMessage(pair, board)
 	return nil
 }
 
-func Repost(channelID, id string) (*Message, error) {
-	m, ok := messageCache[NewPair(channelID, id)]
+//SetMessageStars updates a star count of a starboard entry.
+func SetMessageStars(pair *MessagePair, board string, stars int) error {
+	return setMessageField(pair, board, "stars", stars)
+}
 
-	if !ok {
-		collection := DB.Collection("messages")
-		res := collection.FindOne(context.Background(), bson.D{{"original.channel_id", channelID}, {"original.message_id", id}})
-		if err := res.Err(); err != nil {
-			if err == mongo.ErrNoDocuments {
-				return nil, nil
-			}
-			return nil, err
-		}
-		res.Decode(&m)
+//SetMessageAuthor sets an author of a starboard entry created before authors were stored.
+func SetMessageAuthor(pair *MessagePair, board, authorID string) error {
+	return setMessageField(pair, board, "author_id", authorID)
+}
+
+//SetMessageSnapshot replaces a content snapshot of a starboard entry.
+func SetMessageSnapshot(pair *MessagePair, board string, snapshot *Snapshot) error {
+	return setMessageField(pair, board, "snapshot", snapshot)
+}
+
+//SetMessageFrozen locks or unlocks a starboard entry. Reactions don't change frozen entries.
+func SetMessageFrozen(pair *MessagePair, board string, frozen bool) error {
+	return setMessageField(pair, board, "frozen", frozen)
+}
+
+//SetMessageForced marks a starboard entry as posted by a moderator. Forced entries aren't removed when they lose stars.
+func SetMessageForced(pair *MessagePair, board string, forced bool) error {
+	return setMessageField(pair, board, "forced", forced)
+}
+
+func setMessageField(pair *MessagePair, board, field string, value interface{}) error {
+	collection := DB.Collection("messages")
+	_, err := collection.UpdateOne(context.Background(), bson.M{"original.channel_id": pair.ChannelID, "original.message_id": pair.MessageID, "board": boardFilter(board)}, bson.M{
+		"$set": bson.M{field: value},
+	})
+	if err != nil {
+		return err
 	}
 
-	return &m, nil
+	uncacheMessage(pair, board)
+	return nil
 }
 
-func RepostByStarboard(channelID, id string) (*Message, error) {
-	m, ok := messageCache[NewPair(channelID, id)]
+//Repost returns a starboard entry of an original message on a board.
+func Repost(channelID, id, board string) (*Message, error) {
+	m, ok := cachedMessage(newKey(NewPair(channelID, id), board))
 
 	if !ok {
 		collection := DB.Collection("messages")
-		res := collection.FindOne(context.Background(), bson.D{{"starboard.channel_id", channelID}, {"starboard.message_id", id}})
+		res := collection.FindOne(context.Background(), bson.M{"original.channel_id": channelID, "original.message_id": id, "board": boardFilter(board)})
 		if err := res.Err(); err != nil {
 			if err == mongo.ErrNoDocuments {
 				return nil, nil
 			}
 			return nil, err
 		}
-		res.Decode(&m)
+
+		err := res.Decode(&m)
+		if err != nil {
+			return nil, err
+		}
+		cacheMessage(m)
 	}
 
 	return &m, nil
 }
 
-func Starboard(channelID, id string) (*Message, error) {
-	m, ok := messageCache[NewPair(channelID, id)]
+//Reposts returns starboard entries of an original message on all boards.
+func Reposts(channelID, id string) ([]*Message, error) {
+	collection := DB.Collection("messages")
+	cur, err := collection.Find(context.Background(), bson.M{"original.channel_id": channelID, "original.message_id": id})
+	if err != nil {
+		return nil, err
+	}
 
-	if !ok {
-		collection := DB.Collection("messages")
-		res := collection.FindOne(context.Background(), bson.D{{"starboard.channel_id", channelID}, {"starboard.message_id", id}})
-		if err := res.Err(); err != nil {
-			if err == mongo.ErrNoDocuments {
-				return nil, nil
-			}
-			return nil, err
+	messages := make([]*Message, 0)
+	err = cur.All(context.Background(), &messages)
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+//MessagesByGuild returns all starboard entries of a guild.
+func MessagesByGuild(guildID string) ([]*Message, error) {
+	collection := DB.Collection("messages")
+	cur, err := collection.Find(context.Background(), bson.M{"guild_id": guildID})
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*Message, 0)
+	err = cur.All(context.Background(), &messages)
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+//OnThisDay returns the most starred entries of a guild created on the same day and month as a date in earlier years, in a given timezone.
+func OnThisDay(guildID string, date time.Time, loc *time.Location, limit int) ([]*Message, error) {
+	var (
+		local = date.In(loc)
+		part  = func(op string) bson.M {
+			return bson.M{op: bson.M{"date": "$created_at", "timezone": loc.String()}}
+		}
+	)
+
+	collection := DB.Collection("messages")
+	cur, err := collection.Find(context.Background(), bson.M{
+		"guild_id": guildID,
+		"$expr": bson.M{"$and": bson.A{
+			bson.M{"$eq": bson.A{part("$month"), int(local.Month())}},
+			bson.M{"$eq": bson.A{part("$dayOfMonth"), local.Day()}},
+			bson.M{"$lt": bson.A{part("$year"), local.Year()}},
+		}},
+	}, options.Find().SetSort(bson.D{{Key: "stars", Value: -1}, {Key: "created_at", Value: 1}}).SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*Message, 0)
+	err = cur.All(context.Background(), &messages)
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func RepostByStarboard(channelID, id string) (*Message, error) {
+	collection := DB.Collection("messages")
+	res := collection.FindOne(context.Background(), bson.M{"starboard.channel_id": channelID, "starboard.message_id": id})
+	if err := res.Err(); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
 		}
-		res.Decode(&m)
+		return nil, err
 	}
 
-	return &m, nil
+	m := &Message{}
+	err := res.Decode(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
 }
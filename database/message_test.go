@@ -0,0 +1,145 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestMessageCache(t *testing.T) {
+	var (
+		pair  = NewPair("channel", "message")
+		other = NewPair("channel", "other")
+	)
+
+	tests := []struct {
+		name  string
+		run   func()
+		pair  MessagePair
+		board string
+		found bool
+	}{
+		{"cached", func() { cacheMessage(Message{GuildID: "guild", Original: &pair, Board: "art"}) }, pair, "art", true},
+		{"other board", func() {}, pair, "memes", false},
+		{"uncached", func() { uncacheMessage(&pair, "art") }, pair, "art", false},
+		{"cached again", func() { cacheMessage(Message{GuildID: "guild", Original: &pair, Board: "art"}) }, pair, "art", true},
+		{"board removed", func() {
+			cacheMessage(Message{GuildID: "other guild", Original: &other, Board: "art"})
+			uncacheBoard("guild", "art")
+		}, pair, "art", false},
+		{"board of other guild kept", func() {}, other, "art", true},
+		{"reset", ResetMessageCache, other, "art", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done := make(chan bool)
+			go func() {
+				tt.run()
+				_, found := cachedMessage(newKey(tt.pair, tt.board))
+				done <- found
+			}()
+
+			select {
+			case found := <-done:
+				if found != tt.found {
+					t.Errorf("cachedMessage() found = %v, want %v", found, tt.found)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("message cache deadlocked")
+			}
+		})
+	}
+}
+
+func TestNewSnapshot(t *testing.T) {
+	const (
+		image   = "https://cdn.discordapp.com/attachments/1/2/image.png"
+		spoiler = "https://cdn.discordapp.com/attachments/1/2/SPOILER_image.png"
+		link    = "https://example.com/post"
+		preview = "https://example.com/preview.png"
+	)
+
+	tests := []struct {
+		name        string
+		msg         *discordgo.Message
+		attachments []string
+		spoilers    []string
+		titles      []string
+	}{
+		{
+			name:        "plain attachment",
+			msg:         &discordgo.Message{Attachments: []*discordgo.MessageAttachment{{URL: image, Filename: "image.png"}}},
+			attachments: []string{image},
+		},
+		{
+			name:        "spoilered attachment",
+			msg:         &discordgo.Message{Attachments: []*discordgo.MessageAttachment{{URL: spoiler, Filename: "SPOILER_image.png"}}},
+			attachments: []string{spoiler},
+			spoilers:    []string{spoiler},
+		},
+		{
+			name: "embed",
+			msg: &discordgo.Message{
+				Content: link,
+				Embeds:  []*discordgo.MessageEmbed{{Title: "Post", URL: link, Image: &discordgo.MessageEmbedImage{URL: preview}}},
+			},
+			attachments: []string{preview},
+			titles:      []string{"Post"},
+		},
+		{
+			name: "spoilered embed",
+			msg: &discordgo.Message{
+				Content: "look ||" + link + "||",
+				Embeds:  []*discordgo.MessageEmbed{{URL: link, Image: &discordgo.MessageEmbedImage{URL: preview}}},
+			},
+			attachments: []string{preview},
+			spoilers:    []string{preview},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot := NewSnapshot(tt.msg)
+			if !equalStrings(snapshot.Attachments, tt.attachments) {
+				t.Errorf("NewSnapshot() attachments = %v, want %v", snapshot.Attachments, tt.attachments)
+			}
+
+			if !equalStrings(snapshot.Spoilers, tt.spoilers) {
+				t.Errorf("NewSnapshot() spoilers = %v, want %v", snapshot.Spoilers, tt.spoilers)
+			}
+
+			if !equalStrings(snapshot.EmbedTitles, tt.titles) {
+				t.Errorf("NewSnapshot() embed titles = %v, want %v", snapshot.EmbedTitles, tt.titles)
+			}
+		})
+	}
+}
+
+func TestSnapshotSpoilers(t *testing.T) {
+	tests := []struct {
+		name       string
+		snapshot   *Snapshot
+		uri        string
+		isSpoiler  bool
+		hasSpoiler bool
+	}{
+		{"plain", &Snapshot{Attachments: []string{"https://a/image.png"}}, "https://a/image.png", false, false},
+		{"recorded spoiler", &Snapshot{Attachments: []string{"https://a/image.png"}, Spoilers: []string{"https://a/image.png"}}, "https://a/image.png", true, true},
+		{"old snapshot filename", &Snapshot{Attachments: []string{"https://a/SPOILER_image.png"}}, "https://a/SPOILER_image.png", true, true},
+		{"spoiler in content", &Snapshot{Content: "the ending ||is sad||"}, "https://a/image.png", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.snapshot.IsSpoiler(tt.uri); got != tt.isSpoiler {
+				t.Errorf("IsSpoiler() = %v, want %v", got, tt.isSpoiler)
+			}
+
+			if got := tt.snapshot.HasSpoiler(); got != tt.hasSpoiler {
+				t.Errorf("HasSpoiler() = %v, want %v", got, tt.hasSpoiler)
+			}
+		})
+	}
+}
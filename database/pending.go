@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+//Approval statuses of pending entries.
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+)
+
+//Pending is a message waiting in guild's approval queue before it's posted to a board. Review is the message in the approval channel moderators react to.
+type Pending struct {
+	GuildID    string       `bson:"guild_id" json:"guild_id"`
+	Board      string       `bson:"board" json:"board"`
+	Original   *MessagePair `bson:"original" json:"original"`
+	Review     *MessagePair `bson:"review" json:"review"`
+	Status     string       `bson:"status" json:"status"`
+	ReviewerID string       `bson:"reviewer_id" json:"reviewer_id"`
+	CreatedAt  time.Time    `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time    `bson:"updated_at" json:"updated_at"`
+}
+
+func NewPending(original, review *MessagePair, guildID, board string) *Pending {
+	return &Pending{
+		GuildID:   guildID,
+		Board:     board,
+		Original:  original,
+		Review:    review,
+		Status:    ApprovalPending,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func InsertPending(pending *Pending) error {
+	col := DB.Collection("pending")
+	_, err := col.InsertOne(context.Background(), pending)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+//FindPending returns an approval queue entry of a message on a board or nil if it was never queued.
+func FindPending(pair *MessagePair, board string) (*Pending, error) {
+	return findPending(bson.M{
+		"original.channel_id": pair.ChannelID,
+		"original.message_id": pair.MessageID,
+		"board":               boardFilter(board),
+	})
+}
+
+//PendingByReview returns an approval queue entry by its review message or nil if the message isn't one.
+func PendingByReview(channelID, messageID string) (*Pending, error) {
+	return findPending(bson.M{
+		"review.channel_id": channelID,
+		"review.message_id": messageID,
+	})
+}
+
+func findPending(filter bson.M) (*Pending, error) {
+	col := DB.Collection("pending")
+	res := col.FindOne(context.Background(), filter)
+
+	pending := &Pending{}
+	err := res.Decode(pending)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return pending, nil
+}
+
+//ReviewPending sets a status of a pending entry if it's still pending. Returns false if another moderator has already reviewed it.
+func ReviewPending(review *MessagePair, status, reviewerID string) (bool, error) {
+	col := DB.Collection("pending")
+	res, err := col.UpdateOne(context.Background(), bson.M{
+		"review.channel_id": review.ChannelID,
+		"review.message_id": review.MessageID,
+		"status":            ApprovalPending,
+	}, bson.M{
+		"$set": bson.M{
+			"status":      status,
+			"reviewer_id": reviewerID,
+			"updated_at":  time.Now(),
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return res.ModifiedCount != 0, nil
+}
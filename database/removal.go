@@ -0,0 +1,77 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	//RemovalRatio removes an entry once its stars drop to a fraction of the star requirement.
+	RemovalRatio = "ratio"
+	//RemovalAbsolute removes an entry once its stars drop to a fixed number.
+	RemovalAbsolute = "absolute"
+	//RemovalNever never removes an entry when it loses stars.
+	RemovalNever = "never"
+)
+
+//RemovalThreshold defines when a starboard entry is removed after losing stars.
+type RemovalThreshold struct {
+	Mode  string  `json:"mode" bson:"mode"`
+	Value float64 `json:"value" bson:"value"`
+}
+
+//DefaultRemovalThreshold removes entries that lost half of required stars.
+func DefaultRemovalThreshold() *RemovalThreshold {
+	return &RemovalThreshold{Mode: RemovalRatio, Value: 0.5}
+}
+
+//ParseRemovalThreshold parses "never", a ratio like "50%" or "0.5", or an absolute number of stars like "2".
+func ParseRemovalThreshold(str string) (*RemovalThreshold, error) {
+	switch {
+	case str == "never" || str == "off":
+		return &RemovalThreshold{Mode: RemovalNever}, nil
+	case strings.HasSuffix(str, "%"):
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(str, "%"), 64)
+		if err != nil || percent < 0 || percent > 100 {
+			return nil, fmt.Errorf("unable to parse %v to a percentage", str)
+		}
+		return &RemovalThreshold{Mode: RemovalRatio, Value: percent / 100}, nil
+	case strings.Contains(str, "."):
+		ratio, err := strconv.ParseFloat(str, 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			return nil, fmt.Errorf("unable to parse %v to a ratio between 0 and 1", str)
+		}
+		return &RemovalThreshold{Mode: RemovalRatio, Value: ratio}, nil
+	default:
+		stars, err := strconv.Atoi(str)
+		if err != nil || stars < 0 {
+			return nil, errors.New("removal threshold should be ``never``, a percentage, a ratio or a non-negative number of stars")
+		}
+		return &RemovalThreshold{Mode: RemovalAbsolute, Value: float64(stars)}, nil
+	}
+}
+
+//ShouldRemove checks if an entry with given stars should be removed from a board with given star requirement.
+func (r *RemovalThreshold) ShouldRemove(stars, required int) bool {
+	switch r.Mode {
+	case RemovalNever:
+		return false
+	case RemovalAbsolute:
+		return float64(stars) <= r.Value
+	default:
+		return float64(stars) <= float64(required)*r.Value
+	}
+}
+
+func (r *RemovalThreshold) String() string {
+	switch r.Mode {
+	case RemovalNever:
+		return "never"
+	case RemovalAbsolute:
+		return fmt.Sprintf("%v stars", r.Value)
+	default:
+		return fmt.Sprintf("%v%%", r.Value*100)
+	}
+}
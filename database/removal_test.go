@@ -0,0 +1,65 @@
+package database
+
+import "testing"
+
+func TestParseRemovalThreshold(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    RemovalThreshold
+		wantErr bool
+	}{
+		{in: "never", want: RemovalThreshold{Mode: RemovalNever}},
+		{in: "off", want: RemovalThreshold{Mode: RemovalNever}},
+		{in: "50%", want: RemovalThreshold{Mode: RemovalRatio, Value: 0.5}},
+		{in: "0%", want: RemovalThreshold{Mode: RemovalRatio, Value: 0}},
+		{in: "0.25", want: RemovalThreshold{Mode: RemovalRatio, Value: 0.25}},
+		{in: "2", want: RemovalThreshold{Mode: RemovalAbsolute, Value: 2}},
+		{in: "0", want: RemovalThreshold{Mode: RemovalAbsolute, Value: 0}},
+		{in: "150%", wantErr: true},
+		{in: "-5%", wantErr: true},
+		{in: "1.5", wantErr: true},
+		{in: "-1", wantErr: true},
+		{in: "half", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseRemovalThreshold(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRemovalThreshold() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err == nil && *got != tt.want {
+				t.Errorf("ParseRemovalThreshold() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShouldRemove(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold RemovalThreshold
+		stars     int
+		required  int
+		want      bool
+	}{
+		{"never", RemovalThreshold{Mode: RemovalNever}, 0, 5, false},
+		{"ratio above", RemovalThreshold{Mode: RemovalRatio, Value: 0.5}, 3, 5, false},
+		{"ratio at", RemovalThreshold{Mode: RemovalRatio, Value: 0.5}, 2, 4, true},
+		{"ratio below", RemovalThreshold{Mode: RemovalRatio, Value: 0.5}, 2, 5, true},
+		{"zero ratio keeps starred", RemovalThreshold{Mode: RemovalRatio}, 1, 5, false},
+		{"zero ratio removes unstarred", RemovalThreshold{Mode: RemovalRatio}, 0, 5, true},
+		{"absolute above", RemovalThreshold{Mode: RemovalAbsolute, Value: 2}, 3, 5, false},
+		{"absolute at", RemovalThreshold{Mode: RemovalAbsolute, Value: 2}, 2, 5, true},
+		{"default", *DefaultRemovalThreshold(), 2, 5, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.threshold.ShouldRemove(tt.stars, tt.required); got != tt.want {
+				t.Errorf("ShouldRemove(%v, %v) = %v, want %v", tt.stars, tt.required, got, tt.want)
+			}
+		})
+	}
+}
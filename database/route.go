@@ -0,0 +1,166 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//Routing rule types.
+const (
+	RouteChannel  = "channel"
+	RouteCategory = "category"
+	RouteNSFW     = "nsfw"
+	RouteThread   = "thread"
+)
+
+//RouteRule sends board's entries of messages matching it to a destination channel instead of board's own channel. Rules are checked in order, the first matching one wins.
+type RouteRule struct {
+	Type        string `json:"type" bson:"type"`
+	Value       string `json:"value" bson:"value"`
+	Board       string `json:"board" bson:"board"`
+	Destination string `json:"destination" bson:"destination"`
+}
+
+//RouteContext describes a channel a message was posted in.
+type RouteContext struct {
+	ChannelID      string
+	CategoryID     string
+	ThreadParentID string
+	NSFW           bool
+}
+
+func NewRouteRule(ruleType, value, board, destination string) *RouteRule {
+	return &RouteRule{
+		Type:        ruleType,
+		Value:       value,
+		Board:       board,
+		Destination: destination,
+	}
+}
+
+//IsValidRouteType checks if a string is a known routing rule type.
+func IsValidRouteType(ruleType string) bool {
+	switch ruleType {
+	case RouteChannel, RouteCategory, RouteNSFW, RouteThread:
+		return true
+	}
+	return false
+}
+
+func (r *RouteRule) Matches(ctx *RouteContext) bool {
+	switch r.Type {
+	case RouteChannel:
+		return ctx.ChannelID == r.Value
+	case RouteCategory:
+		return ctx.CategoryID != "" && ctx.CategoryID == r.Value
+	case RouteNSFW:
+		return ctx.NSFW
+	case RouteThread:
+		return ctx.ThreadParentID != "" && ctx.ThreadParentID == r.Value
+	}
+	return false
+}
+
+func (r *RouteRule) String() string {
+	board := r.Board
+	if board == "" {
+		board = DefaultBoardName
+	}
+
+	switch r.Type {
+	case RouteNSFW:
+		return fmt.Sprintf("NSFW channels → <#%v> (%v)", r.Destination, board)
+	case RouteThread:
+		return fmt.Sprintf("threads of <#%v> → <#%v> (%v)", r.Value, r.Destination, board)
+	default:
+		return fmt.Sprintf("%v <#%v> → <#%v> (%v)", r.Type, r.Value, r.Destination, board)
+	}
+}
+
+//Route returns a channel board's entry of a message should be posted to and the rule that matched. Without matching rules board's channel or NSFW channel is used.
+func (g *Guild) Route(b *Board, ctx *RouteContext) (string, *RouteRule) {
+	for _, rule := range g.Routes {
+		if rule.Board == b.Name && rule.Matches(ctx) {
+			return rule.Destination, rule
+		}
+	}
+
+	return b.Destination(ctx.NSFW), nil
+}
+
+func (g *Guild) RoutesToString() string {
+	if len(g.Routes) == 0 {
+		return "none"
+	}
+
+	var sb strings.Builder
+	for ind, rule := range g.Routes {
+		sb.WriteString(fmt.Sprintf("**%v.** %v\n", ind+1, rule))
+	}
+
+	return sb.String()
+}
+
+func AddRouteRule(guildID string, rule *RouteRule) error {
+	col := DB.Collection("guilds")
+
+	res := col.FindOneAndUpdate(context.Background(), bson.M{
+		"guild_id": guildID,
+	}, bson.M{
+		"$set": bson.M{
+			"updated_at": time.Now(),
+		},
+		"$push": bson.M{
+			"routes": rule,
+		},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.After))
+
+	guild := &Guild{}
+	err := res.Decode(guild)
+	if err != nil {
+		return err
+	}
+
+	GuildCache[guildID] = guild
+	return nil
+}
+
+//RemoveRouteRule removes a routing rule by its zero-based position.
+func RemoveRouteRule(guildID string, index int) error {
+	guild, ok := GuildCache[guildID]
+	if !ok {
+		return fmt.Errorf("guild %v is not cached", guildID)
+	}
+
+	if index < 0 || index >= len(guild.Routes) {
+		return fmt.Errorf("routing rule %v doesn't exist", index+1)
+	}
+
+	routes := make([]*RouteRule, 0, len(guild.Routes)-1)
+	routes = append(routes, guild.Routes[:index]...)
+	routes = append(routes, guild.Routes[index+1:]...)
+
+	col := DB.Collection("guilds")
+	res := col.FindOneAndUpdate(context.Background(), bson.M{
+		"guild_id": guildID,
+	}, bson.M{
+		"$set": bson.M{
+			"updated_at": time.Now(),
+			"routes":     routes,
+		},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.After))
+
+	guild = &Guild{}
+	err := res.Decode(guild)
+	if err != nil {
+		return err
+	}
+
+	GuildCache[guildID] = guild
+	return nil
+}
@@ -0,0 +1,230 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//Scheduled jobs. Digests and anniversary posts belong to guilds, other jobs are global and have an empty guild ID.
+const (
+	JobDigest     = "digest"
+	JobOnThisDay  = "onthisday"
+	JobAutoRepair = "autorepair"
+	JobCacheReset = "cachereset"
+)
+
+//Schedule periods.
+const (
+	PeriodDaily   = "daily"
+	PeriodWeekly  = "weekly"
+	PeriodMonthly = "monthly"
+)
+
+//globalIntervals are run intervals of global jobs.
+var globalIntervals = map[string]time.Duration{
+	JobAutoRepair: 24 * time.Hour,
+	JobCacheReset: 10 * time.Hour,
+}
+
+//Schedule is a persisted job with its next run time, so runs missed while Eugen was offline are caught up after a restart.
+type Schedule struct {
+	GuildID   string    `bson:"guild_id" json:"guild_id"`
+	Job       string    `bson:"job" json:"job"`
+	Period    string    `bson:"period" json:"period"`
+	ChannelID string    `bson:"channel_id" json:"channel_id"`
+	Top       int       `bson:"top" json:"top"`
+	NextRun   time.Time `bson:"next_run" json:"next_run"`
+	LastRun   time.Time `bson:"last_run" json:"last_run"`
+}
+
+//NewDigest creates a digest schedule posting top entries of a guild to a channel every week or month.
+func NewDigest(guild *Guild, period, channelID string, top int) *Schedule {
+	s := &Schedule{
+		GuildID:   guild.ID,
+		Job:       JobDigest,
+		Period:    period,
+		ChannelID: channelID,
+		Top:       top,
+	}
+	s.NextRun = s.Next(time.Now(), guild.Location())
+
+	return s
+}
+
+//NewOnThisDay creates a daily schedule reposting entries starboarded on the same date in earlier years.
+func NewOnThisDay(guild *Guild, channelID string) *Schedule {
+	s := &Schedule{
+		GuildID:   guild.ID,
+		Job:       JobOnThisDay,
+		Period:    PeriodDaily,
+		ChannelID: channelID,
+	}
+	s.NextRun = s.Next(time.Now(), guild.Location())
+
+	return s
+}
+
+//NewGlobalSchedule creates a schedule of a global job first run after its interval.
+func NewGlobalSchedule(job string) *Schedule {
+	s := &Schedule{Job: job}
+	s.NextRun = s.Next(time.Now(), time.UTC)
+
+	return s
+}
+
+//IsValidPeriod checks if a string is a known digest period.
+func IsValidPeriod(period string) bool {
+	return period == PeriodWeekly || period == PeriodMonthly
+}
+
+//Next returns the first run time after a given time. Daily jobs run every day, weekly on Mondays and monthly on the first day of a month, at midnight in guild's timezone.
+func (s *Schedule) Next(after time.Time, loc *time.Location) time.Time {
+	if interval, ok := globalIntervals[s.Job]; ok {
+		return after.Add(interval)
+	}
+
+	var (
+		t        = after.In(loc)
+		midnight = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	)
+
+	switch s.Period {
+	case PeriodDaily:
+		return midnight.AddDate(0, 0, 1)
+	case PeriodMonthly:
+		return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+	default:
+		next := midnight.AddDate(0, 0, (8-int(midnight.Weekday()))%7)
+		if !next.After(after) {
+			next = next.AddDate(0, 0, 7)
+		}
+		return next
+	}
+}
+
+//MaxCatchUp is the maximum number of missed runs of a guild job posted after downtime. Older missed runs are skipped.
+const MaxCatchUp = 7
+
+//Missed returns run times due by now, oldest first. Global jobs run once however many runs were missed, guild jobs catch up at most MaxCatchUp latest runs.
+func (s *Schedule) Missed(now time.Time, loc *time.Location) []time.Time {
+	runs := []time.Time{s.NextRun}
+	if _, ok := globalIntervals[s.Job]; ok {
+		return runs
+	}
+
+	for next := s.Next(s.NextRun, loc); !next.After(now); next = s.Next(next, loc) {
+		runs = append(runs, next)
+		if len(runs) > MaxCatchUp {
+			runs = runs[1:]
+		}
+	}
+
+	return runs
+}
+
+//Since returns the start of a period covered by a digest scheduled at a given time.
+func (s *Schedule) Since(scheduled time.Time) time.Time {
+	if s.Period == PeriodMonthly {
+		return scheduled.AddDate(0, -1, 0)
+	}
+	return scheduled.AddDate(0, 0, -7)
+}
+
+func (s *Schedule) String() string {
+	if s.Job == JobOnThisDay {
+		return fmt.Sprintf("**On this day** → <#%v>, next: %v", s.ChannelID, s.NextRun.UTC().Format("2006-01-02 15:04 MST"))
+	}
+
+	return fmt.Sprintf("**%v** top %v → <#%v>, next: %v", strings.Title(s.Period), s.Top, s.ChannelID, s.NextRun.UTC().Format("2006-01-02 15:04 MST"))
+}
+
+func scheduleFilter(guildID, job, period string) bson.M {
+	return bson.M{
+		"guild_id": guildID,
+		"job":      job,
+		"period":   period,
+	}
+}
+
+//SaveSchedule inserts a schedule or replaces an existing one of the same job and period.
+func SaveSchedule(s *Schedule) error {
+	col := DB.Collection("schedules")
+	_, err := col.ReplaceOne(context.Background(), scheduleFilter(s.GuildID, s.Job, s.Period), s, options.Replace().SetUpsert(true))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func RemoveSchedule(guildID, job, period string) error {
+	col := DB.Collection("schedules")
+	res, err := col.DeleteOne(context.Background(), scheduleFilter(guildID, job, period))
+	if err != nil {
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("%v %v doesn't exist", period, job)
+	}
+
+	return nil
+}
+
+//FindSchedule returns a schedule of a job or nil if there's none.
+func FindSchedule(guildID, job, period string) (*Schedule, error) {
+	schedules, err := findSchedules(scheduleFilter(guildID, job, period))
+	if err != nil || len(schedules) == 0 {
+		return nil, err
+	}
+
+	return schedules[0], nil
+}
+
+func GuildSchedules(guildID string) ([]*Schedule, error) {
+	return findSchedules(bson.M{"guild_id": guildID})
+}
+
+//DueSchedules returns schedules which should have run by now.
+func DueSchedules() ([]*Schedule, error) {
+	return findSchedules(bson.M{"next_run": bson.M{"$lte": time.Now()}})
+}
+
+func findSchedules(filter bson.M) ([]*Schedule, error) {
+	col := DB.Collection("schedules")
+	cur, err := col.Find(context.Background(), filter, options.Find().SetSort(bson.M{"next_run": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make([]*Schedule, 0)
+	err = cur.All(context.Background(), &schedules)
+	if err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+//Reschedule recalculates next runs of guild's schedules, e.g. after its timezone was changed.
+func Reschedule(guild *Guild) error {
+	schedules, err := GuildSchedules(guild.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range schedules {
+		s.NextRun = s.Next(time.Now(), guild.Location())
+		err := SaveSchedule(s)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
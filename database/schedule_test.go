@@ -0,0 +1,135 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleNext(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+
+	tests := []struct {
+		name     string
+		schedule *Schedule
+		after    time.Time
+		loc      *time.Location
+		want     time.Time
+	}{
+		{
+			name:     "daily",
+			schedule: &Schedule{Job: JobOnThisDay, Period: PeriodDaily},
+			after:    time.Date(2021, 4, 14, 15, 30, 0, 0, time.UTC),
+			loc:      time.UTC,
+			want:     time.Date(2021, 4, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "daily in guild timezone",
+			schedule: &Schedule{Job: JobOnThisDay, Period: PeriodDaily},
+			after:    time.Date(2021, 4, 14, 15, 30, 0, 0, time.UTC),
+			loc:      tokyo,
+			want:     time.Date(2021, 4, 16, 0, 0, 0, 0, tokyo),
+		},
+		{
+			name:     "weekly midweek",
+			schedule: &Schedule{Job: JobDigest, Period: PeriodWeekly},
+			after:    time.Date(2021, 4, 14, 12, 0, 0, 0, time.UTC),
+			loc:      time.UTC,
+			want:     time.Date(2021, 4, 19, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "weekly on monday",
+			schedule: &Schedule{Job: JobDigest, Period: PeriodWeekly},
+			after:    time.Date(2021, 4, 19, 0, 0, 0, 0, time.UTC),
+			loc:      time.UTC,
+			want:     time.Date(2021, 4, 26, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "weekly on sunday",
+			schedule: &Schedule{Job: JobDigest, Period: PeriodWeekly},
+			after:    time.Date(2021, 4, 18, 23, 0, 0, 0, time.UTC),
+			loc:      time.UTC,
+			want:     time.Date(2021, 4, 19, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "monthly",
+			schedule: &Schedule{Job: JobDigest, Period: PeriodMonthly},
+			after:    time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC),
+			loc:      time.UTC,
+			want:     time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "global",
+			schedule: &Schedule{Job: JobCacheReset},
+			after:    time.Date(2021, 4, 14, 12, 0, 0, 0, time.UTC),
+			loc:      tokyo,
+			want:     time.Date(2021, 4, 14, 22, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.Next(tt.after, tt.loc); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleMissed(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2021, 4, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		schedule *Schedule
+		now      time.Time
+		want     []time.Time
+	}{
+		{
+			name:     "on time",
+			schedule: &Schedule{Job: JobOnThisDay, Period: PeriodDaily, NextRun: day(10)},
+			now:      day(10).Add(time.Minute),
+			want:     []time.Time{day(10)},
+		},
+		{
+			name:     "daily catch up",
+			schedule: &Schedule{Job: JobOnThisDay, Period: PeriodDaily, NextRun: day(10)},
+			now:      day(12).Add(time.Hour),
+			want:     []time.Time{day(10), day(11), day(12)},
+		},
+		{
+			name:     "catch up is capped",
+			schedule: &Schedule{Job: JobOnThisDay, Period: PeriodDaily, NextRun: day(1)},
+			now:      day(20).Add(time.Hour),
+			want:     []time.Time{day(14), day(15), day(16), day(17), day(18), day(19), day(20)},
+		},
+		{
+			name:     "weekly catch up",
+			schedule: &Schedule{Job: JobDigest, Period: PeriodWeekly, NextRun: day(5)},
+			now:      day(20),
+			want:     []time.Time{day(5), day(12), day(19)},
+		},
+		{
+			name:     "global runs once",
+			schedule: &Schedule{Job: JobAutoRepair, NextRun: day(1)},
+			now:      day(20),
+			want:     []time.Time{day(1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.schedule.Missed(tt.now, time.UTC)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Missed() = %v, want %v", got, tt.want)
+			}
+
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("Missed()[%v] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
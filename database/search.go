@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//SearchFilter narrows starboard entries found by a search. Empty fields match everything.
+type SearchFilter struct {
+	GuildID string
+	//Query is matched against snapshotted content, author names and embed titles.
+	Query     string
+	AuthorID  string
+	ChannelID string
+	Before    time.Time
+	After     time.Time
+	MinStars  int
+	MaxStars  int
+}
+
+func (f *SearchFilter) query() bson.M {
+	query := bson.M{"guild_id": f.GuildID}
+	if f.Query != "" {
+		query["$text"] = bson.M{"$search": f.Query}
+	}
+	if f.AuthorID != "" {
+		query["author_id"] = f.AuthorID
+	}
+	if f.ChannelID != "" {
+		query["original.channel_id"] = f.ChannelID
+	}
+
+	created := bson.M{}
+	if !f.Before.IsZero() {
+		created["$lt"] = f.Before
+	}
+	if !f.After.IsZero() {
+		created["$gt"] = f.After
+	}
+	if len(created) != 0 {
+		query["created_at"] = created
+	}
+
+	stars := bson.M{}
+	if f.MinStars > 0 {
+		stars["$gte"] = f.MinStars
+	}
+	if f.MaxStars > 0 {
+		stars["$lte"] = f.MaxStars
+	}
+	if len(stars) != 0 {
+		query["stars"] = stars
+	}
+
+	return query
+}
+
+//ensureSearchIndex creates a text index over snapshots of starboard entries if it doesn't exist yet.
+func ensureSearchIndex(ctx context.Context) error {
+	col := DB.Collection("messages")
+	_, err := col.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "snapshot.content", Value: "text"},
+			{Key: "snapshot.author_name", Value: "text"},
+			{Key: "snapshot.embed_titles", Value: "text"},
+		},
+		Options: options.Index().SetName("search"),
+	})
+	return err
+}
+
+//SearchMessages returns starboard entries matching a filter, best text matches first, then the most starred.
+func SearchMessages(filter *SearchFilter, limit int) ([]*Message, error) {
+	opts := options.Find().SetLimit(int64(limit))
+	if filter.Query != "" {
+		score := bson.M{"$meta": "textScore"}
+		opts.SetProjection(bson.M{"score": score}).SetSort(bson.D{{Key: "score", Value: score}, {Key: "stars", Value: -1}})
+	} else {
+		opts.SetSort(bson.D{{Key: "stars", Value: -1}, {Key: "created_at", Value: -1}})
+	}
+
+	col := DB.Collection("messages")
+	cur, err := col.Find(context.Background(), filter.query(), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*Message, 0)
+	err = cur.All(context.Background(), &messages)
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
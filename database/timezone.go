@@ -0,0 +1,114 @@
+package database
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	//zoneNames maps lowercased IANA zone names to their proper case. Commands receive lowercased arguments, while zone names are case-sensitive.
+	zoneNames     map[string]string
+	zoneNamesOnce sync.Once
+)
+
+//zoneDirs are directories the tz database is usually installed to.
+var zoneDirs = []string{
+	"/usr/share/zoneinfo/",
+	"/usr/share/lib/zoneinfo/",
+	"/usr/lib/locale/TZ/",
+}
+
+//LoadTimezone loads a timezone by its IANA name in any case. "Local" is rejected since it's the timezone of the machine Eugen runs on.
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+
+	var (
+		loc *time.Location
+		err error
+	)
+
+	for _, candidate := range zoneCandidates(name) {
+		loc, err = time.LoadLocation(candidate)
+		if err == nil {
+			break
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if loc == time.Local {
+		return nil, fmt.Errorf("unknown timezone %v", name)
+	}
+	return loc, nil
+}
+
+//zoneCandidates returns names a zone may have: the name itself, its proper case from the tz database and guesses in case the database can't be listed.
+func zoneCandidates(name string) []string {
+	candidates := []string{name}
+	if proper, ok := listZoneNames()[strings.ToLower(name)]; ok {
+		candidates = append(candidates, proper)
+	}
+
+	return append(candidates, strings.ToUpper(name), titleZone(name))
+}
+
+//titleZone capitalizes every word of a zone name, e.g. america/new_york becomes America/New_York.
+func titleZone(name string) string {
+	var (
+		sb    strings.Builder
+		upper = true
+	)
+
+	for _, r := range strings.ToLower(name) {
+		if upper {
+			sb.WriteString(strings.ToUpper(string(r)))
+		} else {
+			sb.WriteRune(r)
+		}
+		upper = r == '/' || r == '_' || r == '-'
+	}
+
+	return sb.String()
+}
+
+//listZoneNames indexes zone names of the installed tz database, or Go's copy of it if there's none.
+func listZoneNames() map[string]string {
+	zoneNamesOnce.Do(func() {
+		zoneNames = make(map[string]string)
+		for _, dir := range zoneDirs {
+			filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+				if err == nil && !info.IsDir() {
+					name := strings.TrimPrefix(path, dir)
+					zoneNames[strings.ToLower(name)] = name
+				}
+				return nil
+			})
+		}
+
+		if len(zoneNames) != 0 {
+			return
+		}
+
+		r, err := zip.OpenReader(filepath.Join(runtime.GOROOT(), "lib", "time", "zoneinfo.zip"))
+		if err != nil {
+			return
+		}
+		defer r.Close()
+
+		for _, f := range r.File {
+			zoneNames[strings.ToLower(f.Name)] = f.Name
+		}
+	})
+
+	return zoneNames
+}
@@ -0,0 +1,32 @@
+package database
+
+import "testing"
+
+func TestLoadTimezone(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"", "UTC", false},
+		{"utc", "UTC", false},
+		{"europe/berlin", "Europe/Berlin", false},
+		{"america/new_york", "America/New_York", false},
+		{"America/Los_Angeles", "America/Los_Angeles", false},
+		{"local", "", true},
+		{"mars/olympus_mons", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := LoadTimezone(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadTimezone() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err == nil && loc.String() != tt.want {
+				t.Errorf("LoadTimezone() = %v, want %v", loc, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//Vote is a single user's reaction with a board emote on a message.
+type Vote struct {
+	GuildID   string    `bson:"guild_id" json:"guild_id"`
+	ChannelID string    `bson:"channel_id" json:"channel_id"`
+	MessageID string    `bson:"message_id" json:"message_id"`
+	UserID    string    `bson:"user_id" json:"user_id"`
+	Emote     string    `bson:"emote" json:"emote"`
+	Bot       bool      `bson:"bot" json:"bot"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+func NewVote(guildID, channelID, messageID, userID, emote string) *Vote {
+	return &Vote{
+		GuildID:   guildID,
+		ChannelID: channelID,
+		MessageID: messageID,
+		UserID:    userID,
+		Emote:     strings.ToLower(emote),
+		CreatedAt: time.Now(),
+	}
+}
+
+func voteFilter(channelID, messageID, emote string) bson.M {
+	return bson.M{
+		"channel_id": channelID,
+		"message_id": messageID,
+		"emote":      strings.ToLower(emote),
+	}
+}
+
+//AddVote inserts a vote unless user has already voted for the message with the same emote.
+func AddVote(vote *Vote) error {
+	col := DB.Collection("votes")
+
+	filter := voteFilter(vote.ChannelID, vote.MessageID, vote.Emote)
+	filter["user_id"] = vote.UserID
+	_, err := col.UpdateOne(context.Background(), filter, bson.M{
+		"$setOnInsert": vote,
+	}, options.Update().SetUpsert(true))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func RemoveVote(channelID, messageID, userID, emote string) error {
+	col := DB.Collection("votes")
+
+	filter := voteFilter(channelID, messageID, emote)
+	filter["user_id"] = userID
+	_, err := col.DeleteOne(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+//RemoveAllVotes removes votes with any emote from a message.
+func RemoveAllVotes(channelID, messageID string) error {
+	col := DB.Collection("votes")
+	_, err := col.DeleteMany(context.Background(), bson.M{
+		"channel_id": channelID,
+		"message_id": messageID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+//Votes returns all votes for a message with an emote.
+func Votes(channelID, messageID, emote string) ([]*Vote, error) {
+	col := DB.Collection("votes")
+	cur, err := col.Find(context.Background(), voteFilter(channelID, messageID, emote))
+	if err != nil {
+		return nil, err
+	}
+
+	votes := make([]*Vote, 0)
+	err = cur.All(context.Background(), &votes)
+	if err != nil {
+		return nil, err
+	}
+
+	return votes, nil
+}
+
+//SyncVotes replaces votes for a message with an emote with the given list of voters. Used when votes collection and Discord disagree, e.g. reactions were added while Eugen was offline. Votes missing from the list are only removed if the list is complete, otherwise voters are only added.
+func SyncVotes(guildID, channelID, messageID, emote string, users []*discordgo.User, complete bool) ([]*Vote, error) {
+	col := DB.Collection("votes")
+
+	if complete {
+		userIDs := make([]string, 0, len(users))
+		for _, user := range users {
+			userIDs = append(userIDs, user.ID)
+		}
+
+		filter := voteFilter(channelID, messageID, emote)
+		filter["user_id"] = bson.M{"$nin": userIDs}
+		_, err := col.DeleteMany(context.Background(), filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(users) != 0 {
+		models := make([]mongo.WriteModel, 0, len(users))
+		for _, user := range users {
+			vote := NewVote(guildID, channelID, messageID, user.ID, emote)
+			filter := voteFilter(channelID, messageID, emote)
+			filter["user_id"] = user.ID
+
+			//Bot flag is set on existing votes too, they may have been added before it was known.
+			models = append(models, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(bson.M{
+				"$setOnInsert": bson.M{
+					"guild_id":   vote.GuildID,
+					"channel_id": vote.ChannelID,
+					"message_id": vote.MessageID,
+					"user_id":    vote.UserID,
+					"emote":      vote.Emote,
+					"created_at": vote.CreatedAt,
+				},
+				"$set": bson.M{
+					"bot": user.Bot,
+				},
+			}).SetUpsert(true))
+		}
+
+		_, err := col.BulkWrite(context.Background(), models)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return Votes(channelID, messageID, emote)
+}
@@ -0,0 +1,190 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//RoleWeight makes votes of a role's members count as several stars.
+type RoleWeight struct {
+	RoleID string `json:"role_id" bson:"role_id"`
+	Weight int    `json:"weight" bson:"weight"`
+}
+
+//IsWeighted checks if votes depend on voters' roles.
+func (g *Guild) IsWeighted() bool {
+	return len(g.VoterRoles) != 0 || len(g.RoleWeights) != 0
+}
+
+//VoteWeight returns how many stars a vote of a member with given roles is worth. Members with weighted roles can always vote, otherwise voting may be restricted to voter roles. The highest weight of member's roles is used.
+func (g *Guild) VoteWeight(roles []string) int {
+	weight := 0
+	for _, role := range roles {
+		for _, rw := range g.RoleWeights {
+			if rw.RoleID == role && rw.Weight > weight {
+				weight = rw.Weight
+			}
+		}
+	}
+
+	if weight != 0 {
+		return weight
+	}
+
+	if len(g.VoterRoles) == 0 {
+		return 1
+	}
+
+	for _, role := range roles {
+		for _, voter := range g.VoterRoles {
+			if voter == role {
+				return 1
+			}
+		}
+	}
+
+	return 0
+}
+
+//IsBannedVoter checks if user's stars don't count.
+func (g *Guild) IsBannedVoter(userID string) bool {
+	for _, id := range g.BannedVoters {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Guild) BannedVotersToString() string {
+	if len(g.BannedVoters) == 0 {
+		return "none"
+	}
+
+	users := make([]string, 0, len(g.BannedVoters))
+	for _, user := range g.BannedVoters {
+		users = append(users, fmt.Sprintf("<@%v>", user))
+	}
+
+	return strings.Join(users, " | ")
+}
+
+func (g *Guild) VoterRolesToString() string {
+	if len(g.VoterRoles) == 0 {
+		return "everyone"
+	}
+
+	roles := make([]string, 0, len(g.VoterRoles))
+	for _, role := range g.VoterRoles {
+		roles = append(roles, fmt.Sprintf("<@&%v>", role))
+	}
+
+	return strings.Join(roles, " | ")
+}
+
+func (g *Guild) RoleWeightsToString() string {
+	if len(g.RoleWeights) == 0 {
+		return "none"
+	}
+
+	var sb strings.Builder
+	for _, rw := range g.RoleWeights {
+		sb.WriteString(fmt.Sprintf("<@&%v>: %v stars\n", rw.RoleID, rw.Weight))
+	}
+
+	return sb.String()
+}
+
+func BanVoter(guildID, userID string) error {
+	return updateVoting(guildID, bson.M{
+		"$set": bson.M{
+			"updated_at": time.Now(),
+		},
+		"$addToSet": bson.M{
+			"banned_voters": userID,
+		},
+	})
+}
+
+func UnbanVoter(guildID, userID string) error {
+	return updateVoting(guildID, bson.M{
+		"$set": bson.M{
+			"updated_at": time.Now(),
+		},
+		"$pull": bson.M{
+			"banned_voters": userID,
+		},
+	})
+}
+
+func AddVoterRole(guildID, roleID string) error {
+	return updateVoting(guildID, bson.M{
+		"$set": bson.M{
+			"updated_at": time.Now(),
+		},
+		"$addToSet": bson.M{
+			"voter_roles": roleID,
+		},
+	})
+}
+
+func RemoveVoterRole(guildID, roleID string) error {
+	return updateVoting(guildID, bson.M{
+		"$set": bson.M{
+			"updated_at": time.Now(),
+		},
+		"$pull": bson.M{
+			"voter_roles": roleID,
+		},
+	})
+}
+
+//SetRoleWeight sets a weight of a role's votes, replacing the previous one.
+func SetRoleWeight(guildID, roleID string, weight int) error {
+	err := RemoveRoleWeight(guildID, roleID)
+	if err != nil {
+		return err
+	}
+
+	return updateVoting(guildID, bson.M{
+		"$set": bson.M{
+			"updated_at": time.Now(),
+		},
+		"$push": bson.M{
+			"role_weights": &RoleWeight{RoleID: roleID, Weight: weight},
+		},
+	})
+}
+
+func RemoveRoleWeight(guildID, roleID string) error {
+	return updateVoting(guildID, bson.M{
+		"$set": bson.M{
+			"updated_at": time.Now(),
+		},
+		"$pull": bson.M{
+			"role_weights": bson.M{"role_id": roleID},
+		},
+	})
+}
+
+func updateVoting(guildID string, update bson.M) error {
+	col := DB.Collection("guilds")
+
+	res := col.FindOneAndUpdate(context.Background(), bson.M{
+		"guild_id": guildID,
+	}, update, options.FindOneAndUpdate().SetReturnDocument(options.After))
+
+	guild := &Guild{}
+	err := res.Decode(guild)
+	if err != nil {
+		return err
+	}
+
+	GuildCache[guildID] = guild
+	return nil
+}
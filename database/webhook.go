@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//Webhook is a webhook Eugen manages in a starboard channel to post entries on behalf of their authors.
+type Webhook struct {
+	ChannelID string `json:"channel_id" bson:"channel_id"`
+	ID        string `json:"id" bson:"id"`
+	Token     string `json:"token" bson:"token"`
+}
+
+//FindWebhook returns a managed webhook of a channel or nil if it doesn't have one.
+func (g *Guild) FindWebhook(channelID string) *Webhook {
+	for _, wh := range g.Webhooks {
+		if wh.ChannelID == channelID {
+			return wh
+		}
+	}
+	return nil
+}
+
+//WebhookByID returns a managed webhook by its ID or nil if the webhook isn't managed by Eugen.
+func (g *Guild) WebhookByID(id string) *Webhook {
+	if id == "" {
+		return nil
+	}
+
+	for _, wh := range g.Webhooks {
+		if wh.ID == id {
+			return wh
+		}
+	}
+	return nil
+}
+
+//SetWebhook stores a managed webhook of a channel, replacing the previous one.
+func SetWebhook(guildID string, webhook *Webhook) error {
+	col := DB.Collection("guilds")
+
+	_, err := col.UpdateOne(context.Background(), bson.M{
+		"guild_id": guildID,
+	}, bson.M{
+		"$pull": bson.M{
+			"webhooks": bson.M{"channel_id": webhook.ChannelID},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	res := col.FindOneAndUpdate(context.Background(), bson.M{
+		"guild_id": guildID,
+	}, bson.M{
+		"$set": bson.M{
+			"updated_at": time.Now(),
+		},
+		"$push": bson.M{
+			"webhooks": webhook,
+		},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.After))
+
+	guild := &Guild{}
+	err = res.Decode(guild)
+	if err != nil {
+		return err
+	}
+
+	GuildCache[guildID] = guild
+	return nil
+}
+
+//RemoveWebhook forgets a managed webhook of a channel, e.g. after it was deleted on Discord's side.
+func RemoveWebhook(guildID, channelID string) error {
+	col := DB.Collection("guilds")
+
+	res := col.FindOneAndUpdate(context.Background(), bson.M{
+		"guild_id": guildID,
+	}, bson.M{
+		"$set": bson.M{
+			"updated_at": time.Now(),
+		},
+		"$pull": bson.M{
+			"webhooks": bson.M{"channel_id": channelID},
+		},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.After))
+
+	guild := &Guild{}
+	err := res.Decode(guild)
+	if err != nil {
+		return err
+	}
+
+	GuildCache[guildID] = guild
+	return nil
+}
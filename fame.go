@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+	"github.com/sirupsen/logrus"
+)
+
+//promoteStarboard cross-posts a starboard entry to guild's hall of fame once it reaches the hall of fame requirement. Entries are promoted automatically only once, forced promotions ignore both the requirement and previous demotions.
+func (se *StarboardEvent) promoteStarboard(react *discordgo.MessageReactions, forced bool) error {
+	if se.guild.HallOfFame == "" {
+		if forced {
+			return errors.New("hall of fame channel is not set")
+		}
+		return nil
+	}
+
+	if se.board.Fame != nil {
+		if forced {
+			return errors.New("entry is already in the hall of fame")
+		}
+		return nil
+	}
+
+	if !forced && (se.board.Promoted || react.Count < se.guild.FameStarsRequired(se.target, se.message.ChannelID)) {
+		return nil
+	}
+
+	ch, err := se.session.Channel(se.message.ChannelID)
+	if err != nil {
+		return err
+	}
+
+	route, err := se.routeContext(ch)
+	if err != nil {
+		return err
+	}
+
+	if route.NSFW {
+		fame, err := se.session.Channel(se.guild.HallOfFame)
+		if err != nil {
+			return err
+		}
+
+		if !fame.NSFW {
+			if forced {
+				return errors.New("hall of fame channel doesn't accept messages from NSFW channels")
+			}
+			return nil
+		}
+	}
+
+	msg, err := se.createEmbed(react, ch)
+	if err != nil {
+		return err
+	}
+
+	if msg == nil {
+		return nil
+	}
+	defer msg.Close()
+
+	embed := msg.Embed()
+	embed.Title = "🏆 Hall of Fame"
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:   "Starboard",
+		Value:  fmt.Sprintf("[Click here desu~](%v)", se.board.Starboard.Link(se.guild.ID)),
+		Inline: true,
+	})
+
+	logrus.Infof("Promoting starboard %v in channel %v to the hall of fame", se.board.Starboard.MessageID, se.board.Starboard.ChannelID)
+	fame, err := sendChannelMessage(se.session, se.guild.HallOfFame, msg.Embeds, msg.Files)
+	if err != nil {
+		return err
+	}
+
+	pair := database.NewPair(fame.ChannelID, fame.ID)
+	err = database.SetMessageFame(se.board.Original, se.board.Board, &pair)
+	if err != nil {
+		return err
+	}
+
+	se.board.Fame = &pair
+	se.board.Promoted = true
+	se.audit(database.AuditPromote, se.board, react.Count, react.Count, "")
+	return nil
+}
+
+//demoteStarboard takes an entry down from the hall of fame. Demoted entries aren't promoted again automatically.
+func (se *StarboardEvent) demoteStarboard() error {
+	if !se.isStarboarded() {
+		return fmt.Errorf("message is not on %v board", se.target.DisplayName())
+	}
+
+	if se.board.Fame == nil {
+		return errors.New("entry is not in the hall of fame")
+	}
+
+	se.removeFame(se.board)
+	err := database.SetMessageFame(se.board.Original, se.board.Board, nil)
+	if err != nil {
+		return err
+	}
+
+	se.audit(database.AuditDemote, se.board, se.board.Stars, se.board.Stars, "")
+	return nil
+}
+
+//removeFame deletes a hall of fame post of an entry if it has one.
+func (se *StarboardEvent) removeFame(entry *database.Message) {
+	if entry.Fame == nil {
+		return
+	}
+
+	logrus.Infof("Removing hall of fame post %v in channel %v", entry.Fame.MessageID, entry.Fame.ChannelID)
+	err := se.session.ChannelMessageDelete(entry.Fame.ChannelID, entry.Fame.MessageID)
+	if err != nil && !utils.IsNotFound(err) {
+		logrus.Warnln("se.session.ChannelMessageDelete():", err)
+	}
+}
+
+//demoteDeletedFame demotes an entry whose hall of fame post was deleted.
+func (se *StarboardEvent) demoteDeletedFame() error {
+	entry, err := database.RepostByFame(se.deleteEvent.ChannelID, se.message.ID)
+	if err != nil || entry == nil {
+		return err
+	}
+
+	logrus.Infof("Hall of fame post %v in channel %v was deleted. Demoting.", se.message.ID, se.deleteEvent.ChannelID)
+	err = database.SetMessageFame(entry.Original, entry.Board, nil)
+	if err != nil {
+		return err
+	}
+
+	se.audit(database.AuditDemote, entry, entry.Stars, entry.Stars, "Hall of fame post was deleted")
+	return nil
+}
@@ -57,6 +57,50 @@ func init() {
 				Name:  "stars",
 				Value: "Stars required to repost a message to starboard channel.",
 			},
+			{
+				Name:  "removal",
+				Value: "When to remove a starboard entry after it loses stars. Accepts ``never``, a percentage of required stars like ``50%``, a ratio like ``0.5`` or an absolute number of stars like ``2``.",
+			},
+			{
+				Name:  "autorepair",
+				Value: "Daily starboard repair switch, accepts ***f or false (case-insensitive)*** to disable and ***t or true*** to enable. See ``{prefix}help repair``.",
+			},
+			{
+				Name:  "accountage",
+				Value: "Minimum account age in days for stars to count. Reactions of bots never count. ***0*** disables the check.",
+			},
+			{
+				Name:  "removebanned",
+				Value: "Automatically removes reactions of banned voters, accepts ***f or false (case-insensitive)*** to disable and ***t or true*** to enable. See ``{prefix}help starban``.",
+			},
+			{
+				Name:  "webhook",
+				Value: "Post starboard entries through a webhook with original author's name and avatar, accepts ***f or false (case-insensitive)*** to disable and ***t or true*** to enable. Eugen needs Manage Webhooks permission.",
+			},
+			{
+				Name:  "spoilers",
+				Value: "How spoilered messages are starboarded. ***blur*** reuploads spoilered media as spoilers (default), ***skip*** doesn't starboard them at all.",
+			},
+			{
+				Name:  "approval",
+				Value: "Approval queue channel. Messages reaching required stars are sent there for moderators to approve with ✅ or reject with ❌ before they're posted. Accepts channel ID, channel mention or ***none*** to post right away.",
+			},
+			{
+				Name:  "halloffame",
+				Value: "Hall of fame channel. Starboard entries reaching hall of fame requirement are cross-posted there once. Accepts channel ID, channel mention or ***none***. See ``{prefix}help promote``.",
+			},
+			{
+				Name:  "famestars",
+				Value: "Stars required to promote an entry to the hall of fame. ***0*** uses 3 times board's requirement.",
+			},
+			{
+				Name:  "timezone",
+				Value: "Server's timezone used to schedule digests, a name like ``Europe/Berlin``. Defaults to UTC. See ``{prefix}help digest``.",
+			},
+			{
+				Name:  "audit",
+				Value: "Audit log channel. Every starboard entry change, forced action, settings change and ban is reported there. Accepts channel ID, channel mention or ***none***. See ``{prefix}help audit``.",
+			},
 		},
 	}).setGuildOnly(true)
 
@@ -82,6 +126,22 @@ func init() {
 		},
 	}
 
+	removalCommand := newCommand("removal", "Sets per channel removal threshold").setExec(removal).setGuildOnly(true)
+	removalCommand.Help.ExtendedHelp = []*discordgo.MessageEmbedField{
+		{
+			Name:  "Usage",
+			Value: "{prefix}removal <channel id or mention> <threshold>",
+		},
+		{
+			Name:  "Channel ID or mention",
+			Value: "Required. It must be a channel on this server!",
+		},
+		{
+			Name:  "Threshold",
+			Value: "Required. ``never``, a percentage of required stars like ``50%``, a ratio like ``0.5``, an absolute number of stars like ``2`` or ``default`` to use server's threshold.",
+		},
+	}
+
 	inviteCmd := newCommand("invite", "Sends an invite link").setExec(invite)
 	setupCommand := newCommand("setup", "Starts an interactive Eugen setup process.").setExec(setup).setGuildOnly(true)
 	basicGroup.addCommand(pingCommand)
@@ -90,6 +150,7 @@ func init() {
 	basicGroup.addCommand(banCommand)
 	basicGroup.addCommand(unbanCommand)
 	basicGroup.addCommand(reqCommand)
+	basicGroup.addCommand(removalCommand)
 	basicGroup.addCommand(inviteCmd)
 	basicGroup.addCommand(setupCommand)
 	basicGroup.addCommand(blacklistCommand)
@@ -208,6 +269,12 @@ func ban(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error
 		}
 	}
 
+	if len(banned) != 0 {
+		entry := database.NewAuditEntry(m.GuildID, database.AuditBan, m.Author.ID)
+		entry.Details = strings.Join(banned, " | ")
+		utils.Audit(s, entry)
+	}
+
 	embed := utils.BaseEmbed(s)
 	embed.Title = "✅ Successfully banned channels"
 	embed.Description = fmt.Sprintf("List of banned channels:\n%v", banned)
@@ -252,6 +319,12 @@ func unban(s *discordgo.Session, m *discordgo.MessageCreate, args []string) erro
 		}
 	}
 
+	if len(unbanned) != 0 {
+		entry := database.NewAuditEntry(m.GuildID, database.AuditUnban, m.Author.ID)
+		entry.Details = strings.Join(unbanned, " | ")
+		utils.Audit(s, entry)
+	}
+
 	embed := utils.BaseEmbed(s)
 	if len(unbanned) > 0 {
 		embed.Title = "✅ Successfully unbanned channels"
@@ -297,6 +370,12 @@ func blacklist(s *discordgo.Session, m *discordgo.MessageCreate, args []string)
 		blacklisted = append(blacklisted, fmt.Sprintf("<@%v>", arg))
 	}
 
+	if len(blacklisted) != 0 {
+		entry := database.NewAuditEntry(m.GuildID, database.AuditBlacklist, m.Author.ID)
+		entry.Details = strings.Join(blacklisted, " | ")
+		utils.Audit(s, entry)
+	}
+
 	embed := utils.BaseEmbed(s)
 	embed.Title = "✅ Successfully blacklisted users"
 	embed.Description = fmt.Sprintf("List of blacklisted users:\n%v", blacklisted)
@@ -340,6 +419,12 @@ func unblacklist(s *discordgo.Session, m *discordgo.MessageCreate, args []string
 		}
 	}
 
+	if len(unblacklisted) != 0 {
+		entry := database.NewAuditEntry(m.GuildID, database.AuditUnblacklist, m.Author.ID)
+		entry.Details = strings.Join(unblacklisted, " | ")
+		utils.Audit(s, entry)
+	}
+
 	embed := utils.BaseEmbed(s)
 	embed.Title = "✅ Successfully unblacklisted users"
 	embed.Description = fmt.Sprintf("List of unblacklisted users:\n%v", unblacklisted)
@@ -414,6 +499,44 @@ func req(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error
 	return nil
 }
 
+func removal(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	ok, err := utils.MemberHasPermission(s, m.GuildID, m.Author.ID, discordgo.PermissionAdministrator|discordgo.PermissionManageServer)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("You don't have enough permissions to run this command.")
+	}
+	if len(args) < 2 {
+		return utils.ErrNotEnoughArguments
+	}
+
+	channelID := strings.Trim(args[0], "<#>")
+	if !utils.IsValidChannel(s, m.GuildID, channelID) {
+		return fmt.Errorf("Unable to get channel <#%v>. Please make sure Eugen has permissions to see the channel.", channelID)
+	}
+
+	var threshold *database.RemovalThreshold
+	if args[1] != "default" {
+		threshold, err = database.ParseRemovalThreshold(args[1])
+		if err != nil {
+			return err
+		}
+	}
+
+	err = database.SetRemovalThreshold(m.GuildID, channelID, threshold)
+	if err != nil {
+		return fmt.Errorf("database error\n%v", err)
+	}
+
+	if threshold == nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully reset <#%v> removal threshold to server's default", channelID))
+	} else {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully set <#%v> removal threshold to %v", channelID, threshold))
+	}
+	return nil
+}
+
 func set(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
 	switch len(args) {
 	case 0:
@@ -438,6 +561,22 @@ func set(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error
 			passedSetting, err = strconv.ParseBool(newSetting)
 		case "ignorebots":
 			passedSetting, err = strconv.ParseBool(newSetting)
+		case "autorepair":
+			passedSetting, err = strconv.ParseBool(newSetting)
+		case "removebanned":
+			passedSetting, err = strconv.ParseBool(newSetting)
+		case "accountage":
+			passedSetting, err = strconv.Atoi(newSetting)
+			if err == nil && passedSetting.(int) < 0 {
+				return errors.New("minimum account age can't be negative")
+			}
+		case "webhook":
+			passedSetting, err = parseWebhookSetting(s, m.GuildID, newSetting)
+		case "spoilers":
+			if newSetting != database.SpoilerBlur && newSetting != database.SpoilerSkip {
+				return fmt.Errorf("unknown spoiler mode %v, it should be either %v or %v", newSetting, database.SpoilerBlur, database.SpoilerSkip)
+			}
+			passedSetting = newSetting
 		case "color":
 			if passedSetting, err = strconv.ParseInt(newSetting, 0, 32); err != nil {
 				if passedSetting, err = strconv.ParseInt("0x"+newSetting, 0, 32); err != nil {
@@ -459,6 +598,13 @@ func set(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error
 			}
 		case "stars":
 			passedSetting, err = strconv.Atoi(newSetting)
+		case "famestars":
+			passedSetting, err = strconv.Atoi(newSetting)
+			if err == nil && passedSetting.(int) < 0 {
+				return errors.New("hall of fame requirement can't be negative")
+			}
+		case "removal":
+			passedSetting, err = database.ParseRemovalThreshold(newSetting)
 		case "emote":
 			emoji, err := utils.GetEmoji(s, m.GuildID, newSetting)
 			if err != nil {
@@ -478,6 +624,25 @@ func set(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error
 			}
 
 			passedSetting = newSetting
+		case "timezone":
+			loc, err := database.LoadTimezone(args[1])
+			if err != nil {
+				return fmt.Errorf("unknown timezone %v, use a name like Europe/Berlin", args[1])
+			}
+			newSetting = loc.String()
+			passedSetting = newSetting
+		case "approval", "audit", "halloffame":
+			if newSetting == "none" || newSetting == "off" {
+				passedSetting = ""
+				break
+			}
+
+			ch, err := guildChannel(s, m.GuildID, newSetting)
+			if err != nil {
+				return err
+			}
+
+			passedSetting = ch.ID
 		case "nsfwstarboard":
 			if strings.HasPrefix(newSetting, "<#") {
 				newSetting = strings.TrimSuffix(strings.TrimPrefix(newSetting, "<#"), ">")
@@ -500,10 +665,17 @@ func set(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error
 			return err
 		}
 
-		err = changeSetting(m.GuildID, setting, passedSetting)
+		err = changeSetting(s, m, setting, passedSetting)
 		if err != nil {
 			return err
 		}
+
+		if setting == "timezone" {
+			err := database.Reschedule(database.GuildCache[m.GuildID])
+			if err != nil {
+				return err
+			}
+		}
 		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully changed ``%v`` to ``%v``", setting, newSetting))
 	default:
 		return errors.New("incorrect command usage. Please use e!help set command for more information")
@@ -530,15 +702,19 @@ func showGuildSettings(s *discordgo.Session, m *discordgo.MessageCreate) {
 		Fields: []*discordgo.MessageEmbedField{
 			{
 				Name:  "Starboard",
-				Value: fmt.Sprintf("**%v**\n**Starboard channel:** %v\n**NSFW starboard channel:** %v", utils.FormatBool(settings.Enabled), utils.FormatChannel(settings.StarboardChannel), utils.FormatChannel(settings.NSFWStarboardChannel)),
+				Value: fmt.Sprintf("**%v**\n**Starboard channel:** %v\n**NSFW starboard channel:** %v\n**Approval channel:** %v\n**Audit channel:** %v\n**Hall of fame channel:** %v (%v stars)", utils.FormatBool(settings.Enabled), utils.FormatChannel(settings.StarboardChannel), utils.FormatChannel(settings.NSFWStarboardChannel), utils.FormatChannel(settings.ApprovalChannel), utils.FormatChannel(settings.AuditChannel), utils.FormatChannel(settings.HallOfFame), settings.FameStarsRequired(settings.DefaultBoard(), "")),
 			},
 			{
 				Name:  "General settings",
-				Value: fmt.Sprintf("**Emote:** %v | **Prefix:** %v | **Color:** %v", settings.StarEmote, settings.Prefix, settings.EmbedColour),
+				Value: fmt.Sprintf("**Emote:** %v | **Prefix:** %v | **Color:** %v | **Timezone:** %v", settings.StarEmote, settings.Prefix, settings.EmbedColour, settings.Location()),
 			},
 			{
 				Name:  "Behaviour settings",
-				Value: fmt.Sprintf("**Selfstar:** %v | **Ignore bots:** %v | **Min stars:** %v", utils.FormatBool(settings.Selfstar), utils.FormatBool(settings.IgnoreBots), settings.MinimumStars),
+				Value: fmt.Sprintf("**Selfstar:** %v | **Ignore bots:** %v | **Min stars:** %v | **Removal:** %v | **Auto repair:** %v | **Webhook:** %v | **Spoilers:** %v | **Min account age:** %v days", utils.FormatBool(settings.Selfstar), utils.FormatBool(settings.IgnoreBots), settings.MinimumStars, settings.RemovalThreshold(""), utils.FormatBool(settings.AutoRepair), utils.FormatBool(settings.Webhook), settings.SpoilerMode(), settings.MinAccountAge),
+			},
+			{
+				Name:  "Named boards",
+				Value: settings.BoardsToString(),
 			},
 			{
 				Name:  "Unique star requirements",
@@ -560,28 +736,46 @@ func showGuildSettings(s *discordgo.Session, m *discordgo.MessageCreate) {
 	})
 }
 
-func changeSetting(guildID, setting string, newSetting interface{}) error {
+//changeSetting sets a guild setting by its bson key and records the change to the audit log.
+func changeSetting(s *discordgo.Session, m *discordgo.MessageCreate, setting string, newSetting interface{}) error {
 	col := database.DB.Collection("guilds")
 
 	res := col.FindOneAndUpdate(context.Background(), bson.M{
-		"guild_id": guildID,
+		"guild_id": m.GuildID,
 	}, bson.M{
 		"$set": bson.M{
 			setting:      newSetting,
 			"updated_at": time.Now(),
 		},
-	}, options.FindOneAndUpdate().SetReturnDocument(options.After))
+	}, options.FindOneAndUpdate().SetReturnDocument(options.Before).SetProjection(bson.M{setting: 1}))
+
+	old := bson.M{}
+	err := res.Decode(&old)
+	if err != nil {
+		return err
+	}
 
 	guild := &database.Guild{}
-	err := res.Decode(guild)
+	err = col.FindOne(context.Background(), bson.M{"guild_id": m.GuildID}).Decode(guild)
 	if err != nil {
 		return err
 	}
 
-	database.GuildCache[guildID] = guild
+	database.GuildCache[m.GuildID] = guild
+
+	entry := database.NewAuditEntry(m.GuildID, database.AuditSettings, m.Author.ID)
+	entry.Details = fmt.Sprintf("``%v``: %v → %v", setting, formatSetting(old[setting]), formatSetting(newSetting))
+	utils.Audit(s, entry)
 	return nil
 }
 
+func formatSetting(setting interface{}) string {
+	if setting == nil || setting == "" {
+		return "none"
+	}
+	return fmt.Sprint(setting)
+}
+
 func invite(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
 	embed := &discordgo.MessageEmbed{
 		Title:       "Thanks for spreading the word!",
@@ -843,6 +1037,10 @@ func setup(s *discordgo.Session, m *discordgo.MessageCreate, args []string) erro
 		err = database.ReplaceGuild(guild)
 		if err != nil {
 			logrus.Warnf("ReplaceGuild(): %v", err)
+		} else {
+			entry := database.NewAuditEntry(m.GuildID, database.AuditSetup, m.Author.ID)
+			entry.Details = fmt.Sprintf("**Starboard channel:** %v | **Min stars:** %v | **Emote:** %v | **Selfstar:** %v | **Color:** %v", starboard, minstars, emote, utils.FormatBool(selfstar), colour)
+			utils.Audit(s, entry)
 		}
 	}
 
@@ -0,0 +1,247 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+)
+
+func init() {
+	boardsGroup := CommandGroup{
+		Name:        "boards",
+		Description: "Named starboards management.",
+		NSFW:        false,
+		Commands:    make(map[string]Command),
+		IsVisible:   true,
+	}
+
+	boardCommand := newCommand("board", "Lists, adds, removes and configures named starboards. Use ``{prefix}help board`` for more info.").setExec(board).setAliases("boards").setGuildOnly(true)
+	boardCommand.Help.ExtendedHelp = []*discordgo.MessageEmbedField{
+		{
+			Name:  "Usage",
+			Value: "{prefix}board ``[add|remove|set]`` ``<name>`` ``[arguments]``",
+		},
+		{
+			Name:  "add",
+			Value: "{prefix}board add ``<name>`` ``<channel>`` ``<emote>`` ``[stars]``. Creates a new board. Minimum stars default to 5.",
+		},
+		{
+			Name:  "remove",
+			Value: "{prefix}board remove ``<name>``. Removes a board along with its starboard entries, posts in its channel are kept. Default board can't be removed.",
+		},
+		{
+			Name:  "set",
+			Value: "{prefix}board set ``<name>`` ``<setting>`` ``<new setting>``. Available settings: ``channel``, ``nsfwchannel``, ``emote``, ``stars``, ``selfstar``, ``nsfw``, ``webhook``. Use ``{prefix}set`` to change the default board.",
+		},
+		{
+			Name:  "NSFW",
+			Value: "New boards ignore messages from NSFW channels until ``nsfw`` is enabled. The default board always accepts them, like the single starboard did before boards existed, and posts them to ``nsfwstarboard`` if it's set.",
+		},
+	}
+
+	boardsGroup.addCommand(boardCommand)
+	CommandGroups["boards"] = boardsGroup
+}
+
+func board(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if len(args) == 0 {
+		return listBoards(s, m)
+	}
+
+	ok, err := utils.MemberHasPermission(s, m.GuildID, m.Author.ID, discordgo.PermissionAdministrator|discordgo.PermissionManageServer)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return utils.ErrNoPermission
+	}
+
+	switch args[0] {
+	case "add":
+		return addBoard(s, m, args[1:])
+	case "remove", "delete":
+		return removeBoard(s, m, args[1:])
+	case "set":
+		return setBoard(s, m, args[1:])
+	default:
+		return errors.New("unknown subcommand " + args[0])
+	}
+}
+
+func listBoards(s *discordgo.Session, m *discordgo.MessageCreate) error {
+	guild := database.GuildCache[m.GuildID]
+
+	embed := utils.BaseEmbed(s)
+	embed.Title = "Starboards"
+	for _, b := range guild.AllBoards() {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  b.DisplayName(),
+			Value: fmt.Sprintf("**Channel:** %v | **NSFW channel:** %v\n**Emote:** %v | **Min stars:** %v\n**Selfstar:** %v | **NSFW:** %v", utils.FormatChannel(b.Channel), utils.FormatChannel(b.NSFWChannel), b.StarEmote, b.MinimumStars, utils.FormatBool(b.Selfstar), utils.FormatBool(b.NSFW)),
+		})
+	}
+
+	s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	return nil
+}
+
+func addBoard(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if len(args) < 3 {
+		return utils.ErrNotEnoughArguments
+	}
+
+	var (
+		guild     = database.GuildCache[m.GuildID]
+		name      = args[0]
+		channelID = strings.Trim(args[1], "<#>")
+		stars     = 5
+	)
+
+	if guild.FindBoard(name) != nil {
+		return fmt.Errorf("board %v already exists", name)
+	}
+
+	if !utils.IsValidChannel(s, m.GuildID, channelID) {
+		return fmt.Errorf("Unable to get channel <#%v>. Please make sure Eugen has permissions to see the channel.", channelID)
+	}
+
+	emote, err := utils.GetEmoji(s, m.GuildID, args[2])
+	if err != nil {
+		return errors.New("argument's either global emoji or not one at all")
+	}
+
+	if len(args) > 3 {
+		stars, err = strconv.Atoi(args[3])
+		if err != nil {
+			return utils.ErrParsingArgument
+		}
+
+		if stars < 1 {
+			return fmt.Errorf("Star requirement should be >= 1, provided star requirement is %v", stars)
+		}
+	}
+
+	err = database.AddBoard(m.GuildID, database.NewBoard(name, channelID, emote, stars))
+	if err != nil {
+		return err
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully added board ``%v`` posting to <#%v>", name, channelID))
+	return nil
+}
+
+func removeBoard(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if len(args) == 0 {
+		return utils.ErrNotEnoughArguments
+	}
+
+	guild := database.GuildCache[m.GuildID]
+	b := guild.FindBoard(args[0])
+	switch {
+	case b == nil:
+		return fmt.Errorf("board %v doesn't exist", args[0])
+	case b.IsDefault():
+		return errors.New("default board can't be removed, use ``set starboard`` to change its channel")
+	}
+
+	deleted, err := database.RemoveBoard(m.GuildID, b.Name)
+	if err != nil {
+		return err
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully removed board ``%v`` and its %v entries", b.Name, deleted))
+	return nil
+}
+
+func setBoard(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if len(args) < 3 {
+		return utils.ErrNotEnoughArguments
+	}
+
+	guild := database.GuildCache[m.GuildID]
+	b := guild.FindBoard(args[0])
+	switch {
+	case b == nil:
+		return fmt.Errorf("board %v doesn't exist", args[0])
+	case b.IsDefault():
+		return errors.New("use ``set`` command to change the default board")
+	}
+
+	var (
+		setting       = args[1]
+		newSetting    = strings.ToLower(args[2])
+		passedSetting interface{}
+		err           error
+	)
+
+	switch setting {
+	case "channel", "nsfwchannel":
+		channelID := strings.Trim(newSetting, "<#>")
+		if !utils.IsValidChannel(s, m.GuildID, channelID) {
+			return fmt.Errorf("Unable to get channel <#%v>. Please make sure Eugen has permissions to see the channel.", channelID)
+		}
+
+		if setting == "nsfwchannel" {
+			setting = "nsfw_channel"
+		}
+		passedSetting = channelID
+	case "emote":
+		emoji, err := utils.GetEmoji(s, m.GuildID, newSetting)
+		if err != nil {
+			return errors.New("argument's either global emoji or not one at all")
+		}
+		passedSetting = emoji
+	case "stars":
+		stars, err := strconv.Atoi(newSetting)
+		if err != nil {
+			return utils.ErrParsingArgument
+		}
+
+		if stars < 1 {
+			return fmt.Errorf("Star requirement should be >= 1, provided star requirement is %v", stars)
+		}
+		passedSetting = stars
+	case "selfstar", "nsfw":
+		passedSetting, err = strconv.ParseBool(newSetting)
+	case "webhook":
+		passedSetting, err = parseWebhookSetting(s, m.GuildID, newSetting)
+	default:
+		return errors.New("unknown setting " + setting)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = database.SetBoardSetting(m.GuildID, b.Name, setting, passedSetting)
+	if err != nil {
+		return err
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully changed ``%v`` of board ``%v`` to ``%v``", args[1], b.Name, newSetting))
+	return nil
+}
+
+//parseWebhookSetting parses a webhook mode switch. Webhook mode can't be enabled unless Eugen is able to create webhooks.
+func parseWebhookSetting(s *discordgo.Session, guildID, str string) (bool, error) {
+	enabled, err := strconv.ParseBool(str)
+	if err != nil || !enabled {
+		return enabled, err
+	}
+
+	ok, err := utils.MemberHasPermission(s, guildID, s.State.User.ID, discordgo.PermissionAdministrator|discordgo.PermissionManageWebhooks)
+	if err != nil {
+		return false, err
+	}
+
+	if !ok {
+		return false, errors.New("Eugen needs Manage Webhooks permission to post through webhooks")
+	}
+
+	return true, nil
+}
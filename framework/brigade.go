@@ -0,0 +1,229 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+)
+
+func init() {
+	brigadeGroup := CommandGroup{
+		Name:        "brigading",
+		Description: "Detection of star raids.",
+		NSFW:        false,
+		Commands:    make(map[string]Command),
+		IsVisible:   true,
+	}
+
+	brigadeCommand := newCommand("brigade", "Configures detection of sudden star surges. Use ``{prefix}help brigade`` for more info.").setExec(brigade).setAliases("antiraid").setGuildOnly(true)
+	brigadeCommand.Help.ExtendedHelp = []*discordgo.MessageEmbedField{
+		{
+			Name:  "Usage",
+			Value: "{prefix}brigade ``[on|off|surge|new|log]`` ``[arguments]``. Messages with suspicious stars are held off the board until moderators review them.",
+		},
+		{
+			Name:  "surge",
+			Value: "{prefix}brigade surge ``<stars>`` ``<minutes>``. Flags messages that got that many stars within the time window. 0 stars disables the check.",
+		},
+		{
+			Name:  "new",
+			Value: "{prefix}brigade new ``<days>`` ``<percent>``. Flags messages with at least that percent of stars from accounts created or members joined less than that many days ago. 0 days disables the check.",
+		},
+		{
+			Name:  "log",
+			Value: "{prefix}brigade log ``<channel|none>``. Channel held messages and their suspicious voters are reported to.",
+		},
+	}
+
+	heldCommand := newCommand("held", "Lists messages held off starboard as possible star raids.").setExec(held).setAliases("flagged").setGuildOnly(true)
+	unflagCommand := newCommand("unflag", "Lets a held message through to starboard when it gets enough stars. Usage: ``{prefix}unflag <message link> [board]``. Use ``{prefix}star`` to post it right away.").setExec(unflag).setGuildOnly(true)
+
+	brigadeGroup.addCommand(brigadeCommand)
+	brigadeGroup.addCommand(heldCommand)
+	brigadeGroup.addCommand(unflagCommand)
+	CommandGroups["brigading"] = brigadeGroup
+}
+
+func brigade(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	guild := database.GuildCache[m.GuildID]
+	if len(args) == 0 {
+		embed := utils.BaseEmbed(s)
+		embed.Title = "Brigade detection"
+		if guild.Brigade == nil {
+			embed.Description = "Disabled. Use ``brigade on`` to enable it."
+		} else {
+			embed.Description = guild.Brigade.String()
+		}
+
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return nil
+	}
+
+	if err := isAdmin(s, m); err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "on", "enable":
+		if guild.Brigade != nil {
+			return errors.New("brigade detection is already enabled")
+		}
+
+		settings := database.DefaultBrigadeSettings()
+		err := changeSetting(s, m, "brigade", settings)
+		if err != nil {
+			return err
+		}
+
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully enabled brigade detection. %v", settings))
+		return nil
+	case "off", "disable":
+		err := changeSetting(s, m, "brigade", nil)
+		if err != nil {
+			return err
+		}
+
+		s.ChannelMessageSend(m.ChannelID, "Successfully disabled brigade detection")
+		return nil
+	}
+
+	if guild.Brigade == nil {
+		return errors.New("brigade detection is disabled, use ``brigade on`` first")
+	}
+
+	settings := *guild.Brigade
+	switch args[0] {
+	case "surge":
+		if len(args) < 3 {
+			return utils.ErrNotEnoughArguments
+		}
+
+		stars, err := strconv.Atoi(args[1])
+		if err != nil {
+			return utils.ErrParsingArgument
+		}
+
+		minutes, err := strconv.Atoi(args[2])
+		if err != nil {
+			return utils.ErrParsingArgument
+		}
+
+		if stars < 0 || minutes < 1 {
+			return errors.New("stars should be >= 0 and minutes >= 1")
+		}
+
+		settings.Stars = stars
+		settings.Window = minutes
+	case "new":
+		if len(args) < 3 {
+			return utils.ErrNotEnoughArguments
+		}
+
+		days, err := strconv.Atoi(args[1])
+		if err != nil {
+			return utils.ErrParsingArgument
+		}
+
+		percent, err := strconv.Atoi(strings.TrimSuffix(args[2], "%"))
+		if err != nil {
+			return utils.ErrParsingArgument
+		}
+
+		if days < 0 || percent < 1 || percent > 100 {
+			return errors.New("days should be >= 0 and percent between 1 and 100")
+		}
+
+		settings.NewDays = days
+		settings.Ratio = float64(percent) / 100
+	case "log":
+		if len(args) < 2 {
+			return utils.ErrNotEnoughArguments
+		}
+
+		if args[1] == "none" || args[1] == "off" {
+			settings.LogChannel = ""
+		} else {
+			ch, err := guildChannel(s, m.GuildID, args[1])
+			if err != nil {
+				return err
+			}
+
+			settings.LogChannel = ch.ID
+		}
+	default:
+		return errors.New("unknown subcommand " + args[0])
+	}
+
+	err := changeSetting(s, m, "brigade", &settings)
+	if err != nil {
+		return err
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully updated brigade detection. %v", &settings))
+	return nil
+}
+
+func held(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if err := isModerator(s, m); err != nil {
+		return err
+	}
+
+	flags, err := database.HeldMessages(m.GuildID)
+	if err != nil {
+		return err
+	}
+
+	embed := utils.BaseEmbed(s)
+	embed.Title = "Held messages"
+	if len(flags) == 0 {
+		embed.Description = "No messages are held."
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return nil
+	}
+
+	guild := database.GuildCache[m.GuildID]
+	for ind, flag := range flags {
+		if ind == 25 {
+			embed.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("And %v more", len(flags)-ind)}
+			break
+		}
+
+		board := database.DefaultBoardName
+		if b := guild.FindBoard(flag.Board); b != nil {
+			board = b.DisplayName()
+		}
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("%v board | %v stars", board, flag.Stars),
+			Value: fmt.Sprintf("[Jump](%v) | %v | %v suspicious voters", flag.Original.Link(m.GuildID), flag.Reason, len(flag.Voters)),
+		})
+	}
+
+	s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	return nil
+}
+
+func unflag(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if err := isModerator(s, m); err != nil {
+		return err
+	}
+
+	msg, board, err := linkedMessage(s, m, args)
+	if err != nil {
+		return err
+	}
+
+	pair := database.NewPair(msg.ChannelID, msg.ID)
+	err = database.ClearFlag(&pair, board.Name)
+	if err != nil {
+		return err
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully let the message through to ``%v`` board", board.DisplayName()))
+	return nil
+}
@@ -4,12 +4,53 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/VTGare/Eugen/database"
 	"github.com/bwmarrin/discordgo"
 )
 
 var (
 	//CommandGroups stores groups of commands.
 	CommandGroups = make(map[string]CommandGroup)
+	//Starboard is set by the main package and lets commands drive starboard events.
+	Starboard StarboardHandler
+)
+
+//StarboardHandler is implemented by the main package to run starboard actions requested by commands.
+type StarboardHandler interface {
+	//Star posts a message to a board regardless of its star count on behalf of a moderator.
+	Star(s *discordgo.Session, guildID, userID string, msg *discordgo.Message, board *database.Board) error
+	//Unstar removes a message from a board on behalf of a moderator.
+	Unstar(s *discordgo.Session, guildID, userID string, msg *discordgo.Message, board *database.Board) error
+	//Promote cross-posts a starboard entry to the hall of fame regardless of its star count.
+	Promote(s *discordgo.Session, guildID, userID string, msg *discordgo.Message, board *database.Board) error
+	//Demote removes a starboard entry from the hall of fame.
+	Demote(s *discordgo.Session, guildID, userID string, msg *discordgo.Message, board *database.Board) error
+	//Freeze stops reactions from changing a starboard entry on behalf of a moderator.
+	Freeze(s *discordgo.Session, guildID, userID string, msg *discordgo.Message, board *database.Board) error
+	//Unfreeze lets reactions change a frozen starboard entry again.
+	Unfreeze(s *discordgo.Session, guildID, userID string, msg *discordgo.Message, board *database.Board) error
+	//Backfill posts a message to a board if it has enough stars and isn't starboarded yet. Returns true if the message was posted.
+	Backfill(s *discordgo.Session, guildID string, msg *discordgo.Message, board *database.Board) (bool, error)
+	//Repair checks that both original and starboard messages of an entry exist, removes orphans and fixes star count.
+	Repair(s *discordgo.Session, guildID string, entry *database.Message) (RepairResult, error)
+	//Show posts a starboard entry to a channel, rendered from its original message or its snapshot. Returns false if an entry has neither.
+	Show(s *discordgo.Session, channelID string, entry *database.Message) (bool, error)
+}
+
+//RepairResult is an outcome of repairing a starboard entry.
+type RepairResult int
+
+const (
+	//RepairOK means an entry didn't need repairs.
+	RepairOK RepairResult = iota
+	//RepairUpdated means an entry's star count was fixed.
+	RepairUpdated
+	//RepairOriginalMissing means an original message was deleted, its starboard message and entry were removed.
+	RepairOriginalMissing
+	//RepairStarboardMissing means a starboard message was deleted, its entry was removed.
+	RepairStarboardMissing
+	//RepairSkipped means an entry belongs to a removed board or is frozen.
+	RepairSkipped
 )
 
 //Command is a structure that defines cmmmand behaviour.
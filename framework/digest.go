@@ -0,0 +1,170 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+)
+
+func init() {
+	digestGroup := CommandGroup{
+		Name:        "digest",
+		Description: "Scheduled \"best of\" digests and \"on this day\" posts.",
+		NSFW:        false,
+		Commands:    make(map[string]Command),
+		IsVisible:   true,
+	}
+
+	digestCommand := newCommand("digest", "Lists, adds and removes weekly and monthly digests of the most starred entries. Use ``{prefix}help digest`` for more info.").setExec(digest).setAliases("digests", "bestof").setGuildOnly(true)
+	digestCommand.Help.ExtendedHelp = []*discordgo.MessageEmbedField{
+		{
+			Name:  "Usage",
+			Value: "{prefix}digest ``[add|remove]`` ``[arguments]``",
+		},
+		{
+			Name:  "add",
+			Value: "{prefix}digest add ``<weekly|monthly>`` ``<channel>`` ``[top]``. Posts top entries of the past week every Monday or of the past month every first day of a month. Top defaults to 10.",
+		},
+		{
+			Name:  "remove",
+			Value: "{prefix}digest remove ``<weekly|monthly>``",
+		},
+		{
+			Name:  "Timezone",
+			Value: "Digests are posted at midnight in server's timezone. Use ``{prefix}set timezone <timezone>`` with a name like ``Europe/Berlin`` to change it from UTC.",
+		},
+	}
+
+	onThisDayCommand := newCommand("onthisday", "Reposts entries starboarded on the same day in earlier years every day. Usage: ``{prefix}onthisday <channel|off>``").setExec(onThisDay).setAliases("anniversary").setGuildOnly(true)
+
+	digestGroup.addCommand(digestCommand)
+	digestGroup.addCommand(onThisDayCommand)
+	CommandGroups["digest"] = digestGroup
+}
+
+func digest(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if len(args) == 0 || args[0] == "list" {
+		return listDigests(s, m)
+	}
+
+	if err := isAdmin(s, m); err != nil {
+		return err
+	}
+
+	if len(args) < 2 {
+		return utils.ErrNotEnoughArguments
+	}
+
+	period := strings.ToLower(args[1])
+	if !database.IsValidPeriod(period) {
+		return fmt.Errorf("unknown period %v, it should be either %v or %v", args[1], database.PeriodWeekly, database.PeriodMonthly)
+	}
+
+	switch args[0] {
+	case "add", "set":
+		return addDigest(s, m, period, args[2:])
+	case "remove", "delete":
+		err := database.RemoveSchedule(m.GuildID, database.JobDigest, period)
+		if err != nil {
+			return err
+		}
+
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully removed %v digest", period))
+		return nil
+	default:
+		return errors.New("unknown subcommand " + args[0])
+	}
+}
+
+func listDigests(s *discordgo.Session, m *discordgo.MessageCreate) error {
+	schedules, err := database.GuildSchedules(m.GuildID)
+	if err != nil {
+		return err
+	}
+
+	guild := database.GuildCache[m.GuildID]
+	embed := utils.BaseEmbed(s)
+	embed.Title = "Digests"
+	embed.Description = fmt.Sprintf("**Timezone:** %v\n", guild.Location())
+
+	if len(schedules) == 0 {
+		embed.Description += "none"
+	}
+
+	for _, schedule := range schedules {
+		embed.Description += schedule.String() + "\n"
+	}
+
+	s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	return nil
+}
+
+func addDigest(s *discordgo.Session, m *discordgo.MessageCreate, period string, args []string) error {
+	if len(args) == 0 {
+		return utils.ErrNotEnoughArguments
+	}
+
+	ch, err := guildChannel(s, m.GuildID, args[0])
+	if err != nil {
+		return err
+	}
+
+	top := 10
+	if len(args) > 1 {
+		top, err = strconv.Atoi(args[1])
+		if err != nil {
+			return utils.ErrParsingArgument
+		}
+
+		if top < 1 || top > 25 {
+			return fmt.Errorf("top should be between 1 and 25, provided top is %v", top)
+		}
+	}
+
+	schedule := database.NewDigest(database.GuildCache[m.GuildID], period, ch.ID, top)
+	err = database.SaveSchedule(schedule)
+	if err != nil {
+		return err
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully scheduled %v digest of top %v entries to <#%v>", period, top, ch.ID))
+	return nil
+}
+
+func onThisDay(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if len(args) == 0 {
+		return listDigests(s, m)
+	}
+
+	if err := isAdmin(s, m); err != nil {
+		return err
+	}
+
+	if args[0] == "off" || args[0] == "none" {
+		err := database.RemoveSchedule(m.GuildID, database.JobOnThisDay, database.PeriodDaily)
+		if err != nil {
+			return err
+		}
+
+		s.ChannelMessageSend(m.ChannelID, "Successfully disabled \"on this day\" posts")
+		return nil
+	}
+
+	ch, err := guildChannel(s, m.GuildID, args[0])
+	if err != nil {
+		return err
+	}
+
+	err = database.SaveSchedule(database.NewOnThisDay(database.GuildCache[m.GuildID], ch.ID))
+	if err != nil {
+		return err
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully scheduled \"on this day\" posts to <#%v>", ch.ID))
+	return nil
+}
@@ -0,0 +1,246 @@
+package framework
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+)
+
+//exportSizeLimit is Discord's upload limit for non-boosted servers.
+const exportSizeLimit = 8 << 20
+
+var exportHTML = template.Must(template.New("export").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Guild}} starboard</title>
+<style>
+body { background: #36393f; color: #dcddde; font-family: sans-serif; margin: 2em; }
+.entries { display: grid; grid-template-columns: repeat(auto-fill, minmax(320px, 1fr)); gap: 1em; }
+.entry { background: #2f3136; border-left: 4px solid #ffc107; border-radius: 4px; padding: 1em; }
+.author { display: flex; align-items: center; gap: .5em; font-weight: bold; }
+.author img { width: 32px; height: 32px; border-radius: 50%; }
+.content { white-space: pre-wrap; margin: .5em 0; }
+.entry img.attachment { max-width: 100%; border-radius: 4px; }
+.spoiler summary { cursor: pointer; color: #b9bbbe; }
+.meta { color: #b9bbbe; font-size: .85em; }
+a { color: #00b0f4; }
+</style>
+</head>
+<body>
+<h1>{{.Guild}} starboard</h1>
+<p class="meta">{{len .Entries}} entries, exported {{.Exported}}</p>
+<div class="entries">
+{{range .Entries}}<div class="entry">
+<div class="author">{{if .AuthorAvatar}}<img src="{{.AuthorAvatar}}" alt="">{{end}}{{.AuthorName}}</div>
+<div class="content">{{.Content}}</div>
+{{range .Media}}{{if .Spoiler}}<details class="spoiler"><summary>Spoiler, click to reveal</summary>{{template "media" .}}</details>{{else}}{{template "media" .}}{{end}}
+{{end}}<div class="meta">⭐ {{.Stars}} | {{.CreatedAt}} | <a href="{{.OriginalURL}}">Original</a> | <a href="{{.StarboardURL}}">Starboard</a></div>
+</div>
+{{end}}</div>
+</body>
+</html>
+{{define "media"}}{{if .Image}}<a href="{{.URL}}"><img class="attachment" src="{{.URL}}" alt="attachment"></a>{{else}}<p><a href="{{.URL}}">{{.Name}}</a></p>{{end}}{{end}}
+`))
+
+//exportEntry is a flat representation of a starboard entry in an export archive.
+type exportEntry struct {
+	Board        string   `json:"board"`
+	AuthorID     string   `json:"author_id"`
+	AuthorName   string   `json:"author_name"`
+	AuthorAvatar string   `json:"author_avatar,omitempty"`
+	ChannelID    string   `json:"channel_id"`
+	Content      string   `json:"content"`
+	Attachments  []string `json:"attachments"`
+	Spoilers     []string `json:"spoilers,omitempty"`
+	Stars        int      `json:"stars"`
+	OriginalURL  string   `json:"original_url"`
+	StarboardURL string   `json:"starboard_url"`
+	CreatedAt    string   `json:"created_at"`
+	//Media describes attachments for the HTML gallery.
+	Media []*exportMedia `json:"-"`
+}
+
+//exportMedia is an attachment in the HTML gallery. Only images are embedded, spoilered media is hidden until clicked.
+type exportMedia struct {
+	URL     string
+	Name    string
+	Image   bool
+	Spoiler bool
+}
+
+func newExportEntry(msg *database.Message) *exportEntry {
+	entry := &exportEntry{
+		Board:        msg.Board,
+		AuthorID:     msg.AuthorID,
+		ChannelID:    msg.Original.ChannelID,
+		Attachments:  make([]string, 0),
+		Stars:        msg.Stars,
+		OriginalURL:  msg.Original.Link(msg.GuildID),
+		StarboardURL: msg.Starboard.Link(msg.GuildID),
+		CreatedAt:    msg.CreatedAt.Format(time.RFC3339),
+	}
+
+	if entry.Board == "" {
+		entry.Board = database.DefaultBoardName
+	}
+
+	if snapshot := msg.Snapshot; snapshot != nil {
+		entry.AuthorName = snapshot.AuthorName
+		entry.AuthorAvatar = snapshot.AuthorAvatar
+		entry.Content = snapshot.Content
+		entry.Attachments = snapshot.Attachments
+		entry.Spoilers = snapshot.Spoilers
+
+		for _, uri := range snapshot.Attachments {
+			name := uri
+			if parsed, err := url.Parse(uri); err == nil {
+				name = path.Base(parsed.Path)
+			}
+
+			entry.Media = append(entry.Media, &exportMedia{
+				URL:     uri,
+				Name:    name,
+				Image:   utils.ImageURLRegex.MatchString(uri),
+				Spoiler: snapshot.IsSpoiler(uri),
+			})
+		}
+	}
+
+	return entry
+}
+
+func exportJSON(entries []*exportEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func exportCSV(entries []*exportEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"created_at", "board", "author_id", "author_name", "channel_id", "stars", "content", "attachments", "original_url", "starboard_url"})
+	for _, e := range entries {
+		w.Write([]string{e.CreatedAt, e.Board, e.AuthorID, e.AuthorName, e.ChannelID, strconv.Itoa(e.Stars), e.Content, strings.Join(e.Attachments, " "), e.OriginalURL, e.StarboardURL})
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func exportHTMLGallery(guildName string, entries []*exportEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	err := exportHTML.Execute(&buf, map[string]interface{}{
+		"Guild":    guildName,
+		"Entries":  entries,
+		"Exported": time.Now().Format("2006-01-02 15:04 MST"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func export(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if err := isAdmin(s, m); err != nil {
+		return err
+	}
+
+	var (
+		guild  = database.GuildCache[m.GuildID]
+		format = "json"
+		board  *database.Board
+	)
+
+	for _, arg := range args {
+		switch arg {
+		case "json", "jsonl", "csv", "html":
+			format = arg
+		default:
+			board = guild.FindBoard(arg)
+			if board == nil {
+				return fmt.Errorf("unknown format or board %v", arg)
+			}
+		}
+	}
+
+	messages, err := database.MessagesByGuild(m.GuildID)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]*exportEntry, 0, len(messages))
+	for _, msg := range messages {
+		if board != nil && msg.Board != board.Name {
+			continue
+		}
+		entries = append(entries, newExportEntry(msg))
+	}
+
+	if len(entries) == 0 {
+		return errors.New("there are no starboard entries to export")
+	}
+
+	var (
+		data []byte
+		name = "starboard-" + time.Now().Format("2006-01-02")
+	)
+
+	switch format {
+	case "csv":
+		data, err = exportCSV(entries)
+		name += ".csv"
+	case "html":
+		data, err = exportHTMLGallery(guild.Name, entries)
+		name += ".html"
+	default:
+		data, err = exportJSON(entries)
+		name += ".jsonl"
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(data) > exportSizeLimit {
+		return fmt.Errorf("export is too large to upload (%v MB), try exporting a single board or a different format", len(data)>>20)
+	}
+
+	embed := utils.BaseEmbed(s)
+	embed.Title = "✅ Starboard exported"
+	embed.Description = fmt.Sprintf("**Entries:** %v\n**Format:** %v", len(entries), format)
+
+	_, err = s.ChannelMessageSendComplex(m.ChannelID, &discordgo.MessageSend{
+		Embed: embed,
+		Files: []*discordgo.File{
+			{
+				Name:   name,
+				Reader: bytes.NewReader(data),
+			},
+		},
+	})
+	return err
+}
@@ -0,0 +1,156 @@
+package framework
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	//importParsers are tried in order when no parser is specified.
+	importParsers = []importParser{
+		&eugenParser{},
+		&jumpFieldParser{},
+		&genericParser{},
+	}
+
+	starCountRegex = regexp.MustCompile(`(\d+)`)
+)
+
+//importedEntry is an original message found in other bot's starboard post.
+type importedEntry struct {
+	Original *database.MessagePair
+	Stars    int
+}
+
+//importParser extracts an original message from a starboard post made by another bot.
+type importParser interface {
+	Name() string
+	Parse(msg *discordgo.Message) (*importedEntry, bool)
+}
+
+func findImportParser(name string) importParser {
+	for _, p := range importParsers {
+		if p.Name() == name {
+			return p
+		}
+	}
+	return nil
+}
+
+//parseImport tries all parsers until one recognizes the post.
+func parseImport(msg *discordgo.Message) (*importedEntry, bool) {
+	for _, p := range importParsers {
+		if entry, ok := p.Parse(msg); ok {
+			return entry, true
+		}
+	}
+	return nil, false
+}
+
+func linkedPair(str string) (*database.MessagePair, bool) {
+	_, channelID, messageID, ok := utils.ParseMessageLink(str)
+	if !ok {
+		return nil, false
+	}
+
+	pair := database.NewPair(channelID, messageID)
+	return &pair, true
+}
+
+func firstNumber(str string) int {
+	num, _ := strconv.Atoi(starCountRegex.FindString(str))
+	return num
+}
+
+//eugenParser parses posts made by Eugen, e.g. from a previous installation or another instance.
+type eugenParser struct{}
+
+func (*eugenParser) Name() string {
+	return "eugen"
+}
+
+func (*eugenParser) Parse(msg *discordgo.Message) (*importedEntry, bool) {
+	if len(msg.Embeds) == 0 {
+		return nil, false
+	}
+
+	embed := msg.Embeds[0]
+	for _, field := range embed.Fields {
+		if field.Name != "Original message" {
+			continue
+		}
+
+		if pair, ok := linkedPair(field.Value); ok {
+			entry := &importedEntry{Original: pair}
+			if embed.Footer != nil {
+				entry.Stars = firstNumber(embed.Footer.Text)
+			}
+			return entry, true
+		}
+	}
+
+	return nil, false
+}
+
+//jumpFieldParser parses posts of bots that put a jump link into a "Source", "Original" or "Jump" field and star count into message content, like Starboard or Carl-bot.
+type jumpFieldParser struct{}
+
+func (*jumpFieldParser) Name() string {
+	return "jump"
+}
+
+func (*jumpFieldParser) Parse(msg *discordgo.Message) (*importedEntry, bool) {
+	if len(msg.Embeds) == 0 {
+		return nil, false
+	}
+
+	for _, field := range msg.Embeds[0].Fields {
+		name := strings.ToLower(field.Name)
+		if !strings.Contains(name, "source") && !strings.Contains(name, "original") && !strings.Contains(name, "jump") {
+			continue
+		}
+
+		if pair, ok := linkedPair(field.Value); ok {
+			return &importedEntry{Original: pair, Stars: firstNumber(msg.Content)}, true
+		}
+	}
+
+	return nil, false
+}
+
+//genericParser looks for the first message link anywhere in a post.
+type genericParser struct{}
+
+func (*genericParser) Name() string {
+	return "generic"
+}
+
+func (*genericParser) Parse(msg *discordgo.Message) (*importedEntry, bool) {
+	candidates := []string{msg.Content}
+	for _, embed := range msg.Embeds {
+		candidates = append(candidates, embed.URL, embed.Description)
+		if embed.Author != nil {
+			candidates = append(candidates, embed.Author.URL)
+		}
+		for _, field := range embed.Fields {
+			candidates = append(candidates, field.Value)
+		}
+	}
+
+	for _, str := range candidates {
+		if pair, ok := linkedPair(str); ok {
+			entry := &importedEntry{Original: pair, Stars: firstNumber(msg.Content)}
+			if entry.Stars == 0 && len(msg.Embeds) != 0 && msg.Embeds[0].Footer != nil {
+				entry.Stars = firstNumber(msg.Embeds[0].Footer.Text)
+			}
+			return entry, true
+		}
+	}
+
+	return nil, false
+}
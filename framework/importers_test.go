@@ -0,0 +1,116 @@
+package framework
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const importLink = "https://discord.com/channels/1/2/3"
+
+func TestImportParsers(t *testing.T) {
+	tests := []struct {
+		name   string
+		parser string
+		msg    *discordgo.Message
+		ok     bool
+		stars  int
+	}{
+		{
+			name:   "eugen post",
+			parser: "eugen",
+			msg: &discordgo.Message{Embeds: []*discordgo.MessageEmbed{{
+				Fields: []*discordgo.MessageEmbedField{{Name: "Original message", Value: "[Click here desu~](" + importLink + ")"}},
+				Footer: &discordgo.MessageEmbedFooter{Text: "⭐ 12 (14 reactions)"},
+			}}},
+			ok:    true,
+			stars: 12,
+		},
+		{
+			name:   "eugen parser ignores other fields",
+			parser: "eugen",
+			msg: &discordgo.Message{Embeds: []*discordgo.MessageEmbed{{
+				Fields: []*discordgo.MessageEmbedField{{Name: "Source", Value: importLink}},
+			}}},
+		},
+		{
+			name:   "jump field",
+			parser: "jump",
+			msg: &discordgo.Message{
+				Content: "⭐ **7** | #general",
+				Embeds: []*discordgo.MessageEmbed{{
+					Fields: []*discordgo.MessageEmbedField{{Name: "Source", Value: "[Jump!](" + importLink + ")"}},
+				}},
+			},
+			ok:    true,
+			stars: 7,
+		},
+		{
+			name:   "jump field without embeds",
+			parser: "jump",
+			msg:    &discordgo.Message{Content: importLink},
+		},
+		{
+			name:   "generic link in content",
+			parser: "generic",
+			msg:    &discordgo.Message{Content: "5 stars " + importLink},
+			ok:     true,
+			stars:  5,
+		},
+		{
+			name:   "generic stars from footer",
+			parser: "generic",
+			msg: &discordgo.Message{Embeds: []*discordgo.MessageEmbed{{
+				Author: &discordgo.MessageEmbedAuthor{URL: importLink},
+				Footer: &discordgo.MessageEmbedFooter{Text: "9 stars"},
+			}}},
+			ok:    true,
+			stars: 9,
+		},
+		{
+			name:   "generic without a link",
+			parser: "generic",
+			msg:    &discordgo.Message{Content: "just a message"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, ok := findImportParser(tt.parser).Parse(tt.msg)
+			if ok != tt.ok {
+				t.Fatalf("Parse() ok = %v, want %v", ok, tt.ok)
+			}
+
+			if !ok {
+				return
+			}
+
+			if entry.Original.ChannelID != "2" || entry.Original.MessageID != "3" {
+				t.Errorf("Parse() original = %v, want 2 3", entry.Original)
+			}
+
+			if entry.Stars != tt.stars {
+				t.Errorf("Parse() stars = %v, want %v", entry.Stars, tt.stars)
+			}
+		})
+	}
+}
+
+func TestParseImportOrder(t *testing.T) {
+	msg := &discordgo.Message{
+		Content: "3",
+		Embeds: []*discordgo.MessageEmbed{{
+			Fields: []*discordgo.MessageEmbedField{{Name: "Original message", Value: importLink}},
+			Footer: &discordgo.MessageEmbedFooter{Text: "11"},
+		}},
+	}
+
+	entry, ok := parseImport(msg)
+	if !ok {
+		t.Fatal("parseImport() didn't recognize an Eugen post")
+	}
+
+	if entry.Stars != 11 {
+		t.Errorf("parseImport() stars = %v, want 11 from Eugen's footer", entry.Stars)
+	}
+}
@@ -0,0 +1,287 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	leaderboardLimit    = 50
+	leaderboardPageSize = 10
+	//randomSample is how many entries are sampled by the random command to find one that can be shown.
+	randomSample = 10
+)
+
+func init() {
+	leaderboardGroup := CommandGroup{
+		Name:        "leaderboard",
+		Description: "Most starred users, messages and channels.",
+		NSFW:        false,
+		Commands:    make(map[string]Command),
+		IsVisible:   true,
+	}
+
+	leaderboardCommand := newCommand("leaderboard", "Shows most starred users, messages or channels. Use ``{prefix}help leaderboard`` for more info.").setExec(leaderboard).setAliases("top", "lb").setGuildOnly(true)
+	leaderboardCommand.Help.ExtendedHelp = []*discordgo.MessageEmbedField{
+		{
+			Name:  "Usage",
+			Value: "{prefix}leaderboard ``<users|messages|channels>`` ``[week|month|all]`` ``[board]``",
+		},
+		{
+			Name:  "Time range",
+			Value: "Optional. ``week`` or ``month`` to only count starboard entries created during the last week or month. All-time by default.",
+		},
+		{
+			Name:  "Board",
+			Value: "Optional. Name of a board to count. All boards are counted by default.",
+		},
+	}
+
+	randomCommand := newCommand("random", "Shows a random starboard entry. Use ``{prefix}help random`` for more info.").setExec(random).setAliases("rand", "roll").setGuildOnly(true)
+	randomCommand.Help.ExtendedHelp = []*discordgo.MessageEmbedField{
+		{
+			Name:  "Usage",
+			Value: "{prefix}random ``[channel]`` ``[@user]`` ``[min stars]``",
+		},
+		{
+			Name:  "Filters",
+			Value: "Optional and combinable. A channel mention to only pick entries from that channel, a user mention to only pick their entries and a number to only pick entries with at least that many stars.",
+		},
+		{
+			Name:  "NSFW",
+			Value: "Entries from NSFW channels are only shown in NSFW channels.",
+		},
+	}
+
+	leaderboardGroup.addCommand(leaderboardCommand)
+	leaderboardGroup.addCommand(randomCommand)
+	CommandGroups["leaderboard"] = leaderboardGroup
+}
+
+func leaderboard(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if len(args) == 0 {
+		return utils.ErrNotEnoughArguments
+	}
+
+	var (
+		guild  = database.GuildCache[m.GuildID]
+		filter = &database.LeaderboardFilter{GuildID: m.GuildID}
+		period = "all-time"
+	)
+
+	for _, arg := range args[1:] {
+		if since, ok := parsePeriod(arg); ok {
+			filter.Since = since
+			period = arg
+			continue
+		}
+
+		b := guild.FindBoard(arg)
+		if b == nil {
+			return fmt.Errorf("unknown time range or board %v", arg)
+		}
+		filter.Board = &b.Name
+	}
+
+	var (
+		pages []*discordgo.MessageEmbed
+		err   error
+	)
+
+	switch args[0] {
+	case "users", "user", "authors":
+		pages, err = topUsersPages(s, filter)
+	case "messages", "message", "posts":
+		pages, err = topMessagesPages(s, filter)
+	case "channels", "channel":
+		pages, err = topChannelsPages(s, filter)
+	default:
+		return errors.New("unknown leaderboard " + args[0])
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(pages) == 0 {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("No starboard entries found (%v).", period))
+		return nil
+	}
+
+	for _, page := range pages {
+		page.Title = fmt.Sprintf("%v (%v)", page.Title, period)
+	}
+
+	return utils.NewPaginator(s, m.ChannelID, m.Author.ID, pages).Run()
+}
+
+func random(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	filter := &database.RandomFilter{GuildID: m.GuildID}
+	for _, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, "<#"):
+			ch, err := guildChannel(s, m.GuildID, arg)
+			if err != nil {
+				return err
+			}
+			filter.ChannelID = ch.ID
+		case strings.HasPrefix(arg, "<@"):
+			filter.AuthorID = strings.Trim(arg, "<@!>")
+		default:
+			stars, err := strconv.Atoi(arg)
+			if err != nil || stars < 0 {
+				return fmt.Errorf("unknown filter %v, it should be a channel, a user mention or a number of stars", arg)
+			}
+			filter.MinStars = stars
+		}
+	}
+
+	entries, err := newEntryFilter(s, m.ChannelID)
+	if err != nil {
+		return err
+	}
+
+	messages, err := database.RandomMessages(filter, randomSample)
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range messages {
+		if !entries.allowed(msg) {
+			continue
+		}
+
+		shown, err := Starboard.Show(s, m.ChannelID, msg)
+		if err != nil {
+			return err
+		}
+
+		if shown {
+			return nil
+		}
+	}
+
+	s.ChannelMessageSend(m.ChannelID, "No starboard entries found.")
+	return nil
+}
+
+//entryFilter tells which starboard entries can be shown in a channel. Entries from NSFW channels are only shown in NSFW channels.
+type entryFilter struct {
+	session  *discordgo.Session
+	nsfw     bool
+	channels map[string]bool
+}
+
+func newEntryFilter(s *discordgo.Session, channelID string) (*entryFilter, error) {
+	nsfw, err := utils.IsNSFWChannel(s, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entryFilter{session: s, nsfw: nsfw, channels: make(map[string]bool)}, nil
+}
+
+//allowed checks if an entry can be shown. Entries from channels Eugen can't see are never shown since they may be NSFW.
+func (f *entryFilter) allowed(entry *database.Message) bool {
+	if f.nsfw {
+		return true
+	}
+
+	nsfw, ok := f.channels[entry.Original.ChannelID]
+	if !ok {
+		var err error
+		nsfw, err = utils.IsNSFWChannel(f.session, entry.Original.ChannelID)
+		if err != nil {
+			return false
+		}
+		f.channels[entry.Original.ChannelID] = nsfw
+	}
+
+	return !nsfw
+}
+
+//parsePeriod returns a beginning of a leaderboard time range. Zero time stands for all-time.
+func parsePeriod(arg string) (time.Time, bool) {
+	switch arg {
+	case "week", "weekly", "w":
+		return time.Now().AddDate(0, 0, -7), true
+	case "month", "monthly", "m":
+		return time.Now().AddDate(0, -1, 0), true
+	case "all", "alltime", "all-time":
+		return time.Time{}, true
+	}
+
+	return time.Time{}, false
+}
+
+//leaderboardPages splits leaderboard lines into embeds of leaderboardPageSize lines.
+func leaderboardPages(s *discordgo.Session, title string, lines []string) []*discordgo.MessageEmbed {
+	pages := make([]*discordgo.MessageEmbed, 0)
+	for i := 0; i < len(lines); i += leaderboardPageSize {
+		end := i + leaderboardPageSize
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		embed := utils.BaseEmbed(s)
+		embed.Title = title
+		embed.Description = strings.Join(lines[i:end], "\n")
+		pages = append(pages, embed)
+	}
+
+	return pages
+}
+
+func topUsersPages(s *discordgo.Session, filter *database.LeaderboardFilter) ([]*discordgo.MessageEmbed, error) {
+	users, err := database.TopUsers(filter, leaderboardLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]string, 0, len(users))
+	for ind, user := range users {
+		lines = append(lines, fmt.Sprintf("**%v.** <@%v> — ⭐ %v in %v entries", ind+1, user.ID, user.Stars, user.Entries))
+	}
+
+	return leaderboardPages(s, "Most starred users", lines), nil
+}
+
+func topChannelsPages(s *discordgo.Session, filter *database.LeaderboardFilter) ([]*discordgo.MessageEmbed, error) {
+	channels, err := database.TopChannels(filter, leaderboardLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]string, 0, len(channels))
+	for ind, ch := range channels {
+		lines = append(lines, fmt.Sprintf("**%v.** <#%v> — ⭐ %v in %v entries", ind+1, ch.ID, ch.Stars, ch.Entries))
+	}
+
+	return leaderboardPages(s, "Most starred channels", lines), nil
+}
+
+func topMessagesPages(s *discordgo.Session, filter *database.LeaderboardFilter) ([]*discordgo.MessageEmbed, error) {
+	messages, err := database.TopMessages(filter, leaderboardLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]string, 0, len(messages))
+	for ind, msg := range messages {
+		author := "unknown"
+		if msg.AuthorID != "" {
+			author = fmt.Sprintf("<@%v>", msg.AuthorID)
+		}
+
+		lines = append(lines, fmt.Sprintf("**%v.** ⭐ %v | %v in <#%v> | [Original](%v) | [Starboard](%v)", ind+1, msg.Stars, author, msg.Original.ChannelID, msg.Original.Link(msg.GuildID), msg.Starboard.Link(msg.GuildID)))
+	}
+
+	return leaderboardPages(s, "Most starred messages", lines), nil
+}
@@ -0,0 +1,506 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	//running stores guilds with a maintenance job in progress, only one job per guild is allowed.
+	running   = make(map[string]string)
+	runningMu sync.Mutex
+)
+
+func init() {
+	maintenanceGroup := CommandGroup{
+		Name:        "maintenance",
+		Description: "Starboard maintenance commands.",
+		NSFW:        false,
+		Commands:    make(map[string]Command),
+		IsVisible:   true,
+	}
+
+	scanCommand := newCommand("scan", "Scans channel history and posts messages that have enough stars but aren't on starboard. Use ``{prefix}help scan`` for more info.").setExec(scan).setAliases("backfill").setGuildOnly(true)
+	scanCommand.Help.ExtendedHelp = []*discordgo.MessageEmbedField{
+		{
+			Name:  "Usage",
+			Value: "{prefix}scan ``<channel>`` ``[since]``",
+		},
+		{
+			Name:  "Since",
+			Value: "Optional. How far back to scan: a relative time like ``12h``, ``7d``, ``2w``, ``3m``, ``1y`` or a date like ``2021-01-31``. Whole channel history is scanned by default.",
+		},
+	}
+
+	repairCommand := newCommand("repair", "Checks every starboard entry of this server, removes entries with deleted original or starboard messages and fixes star counts. Use ``{prefix}set autorepair true`` to repair daily.").setExec(repair).setAliases("reconcile").setGuildOnly(true)
+
+	importCommand := newCommand("import", "Imports an existing starboard channel of another bot. Use ``{prefix}help import`` for more info.").setExec(importStarboard).setGuildOnly(true)
+	importCommand.Help.ExtendedHelp = []*discordgo.MessageEmbedField{
+		{
+			Name:  "Usage",
+			Value: "{prefix}import ``<channel>`` ``[board]`` ``[parser]``",
+		},
+		{
+			Name:  "Board",
+			Value: "Optional. Name of a board to import entries to. Default board by default.",
+		},
+		{
+			Name:  "Parser",
+			Value: "Optional. Layout of other bot's posts: ``eugen``, ``jump`` for bots with a jump link field like Starboard or Carl-bot, or ``generic`` for any message link. All parsers are tried by default.",
+		},
+		{
+			Name:  "Imported posts",
+			Value: "Eugen can't edit other bots' messages, so an imported post is replaced with Eugen's own post the next time its star count changes.",
+		},
+	}
+
+	exportCommand := newCommand("export", "Uploads an archive of every starboard entry on this server. Usage: ``{prefix}export [json|csv|html] [board]``").setExec(export).setAliases("archive").setGuildOnly(true)
+
+	maintenanceGroup.addCommand(scanCommand)
+	maintenanceGroup.addCommand(exportCommand)
+	maintenanceGroup.addCommand(importCommand)
+	maintenanceGroup.addCommand(repairCommand)
+	CommandGroups["maintenance"] = maintenanceGroup
+}
+
+//isAdmin checks if a member can manage a server.
+func isAdmin(s *discordgo.Session, m *discordgo.MessageCreate) error {
+	ok, err := utils.MemberHasPermission(s, m.GuildID, m.Author.ID, discordgo.PermissionAdministrator|discordgo.PermissionManageServer)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return utils.ErrNoPermission
+	}
+
+	return nil
+}
+
+//startJob marks a maintenance job as running in a guild. Returned function marks it as finished.
+func startJob(guildID, name string) (func(), error) {
+	runningMu.Lock()
+	defer runningMu.Unlock()
+
+	if job, ok := running[guildID]; ok {
+		return nil, fmt.Errorf("%v is already running on this server, please wait until it's finished", job)
+	}
+
+	running[guildID] = name
+	return func() {
+		runningMu.Lock()
+		delete(running, guildID)
+		runningMu.Unlock()
+	}, nil
+}
+
+//progressMessage is a live-updating embed reporting progress of a long running command.
+type progressMessage struct {
+	session *discordgo.Session
+	message *discordgo.Message
+	embed   *discordgo.MessageEmbed
+	updated time.Time
+}
+
+func newProgressMessage(s *discordgo.Session, channelID, title string) (*progressMessage, error) {
+	embed := utils.BaseEmbed(s)
+	embed.Title = title
+	embed.Description = "Starting..."
+
+	msg, err := s.ChannelMessageSendEmbed(channelID, embed)
+	if err != nil {
+		return nil, err
+	}
+
+	return &progressMessage{session: s, message: msg, embed: embed, updated: time.Now()}, nil
+}
+
+//Update edits progress embed. Updates are throttled to one per 5 seconds unless forced.
+func (p *progressMessage) Update(description string, force bool) {
+	if !force && time.Since(p.updated) < 5*time.Second {
+		return
+	}
+
+	p.embed.Description = description
+	p.embed.Timestamp = utils.EmbedTimestamp()
+	p.updated = time.Now()
+
+	_, err := p.session.ChannelMessageEditEmbed(p.message.ChannelID, p.message.ID, p.embed)
+	if err != nil {
+		logrus.Warnln("progressMessage.Update():", err)
+	}
+}
+
+func (p *progressMessage) Finish(title, description string) {
+	p.embed.Title = title
+	p.Update(description, true)
+}
+
+//canStarboard checks if a message's author allows it to be starboarded.
+func canStarboard(s *discordgo.Session, guild *database.Guild, msg *discordgo.Message) bool {
+	if msg.Author == nil {
+		return false
+	}
+
+	if msg.Author.ID == s.State.User.ID || guild.WebhookByID(msg.WebhookID) != nil {
+		return false
+	}
+
+	if msg.Author.Bot && guild.IgnoreBots {
+		return false
+	}
+
+	return !guild.IsBlacklisted(msg.Author.ID)
+}
+
+func scan(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if err := isAdmin(s, m); err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return utils.ErrNotEnoughArguments
+	}
+
+	var (
+		guild     = database.GuildCache[m.GuildID]
+		channelID = strings.Trim(args[0], "<#>")
+		since     time.Time
+	)
+
+	if !utils.IsValidChannel(s, m.GuildID, channelID) {
+		return fmt.Errorf("Unable to get channel <#%v>. Please make sure Eugen has permissions to see the channel.", channelID)
+	}
+
+	if guild.IsBanned(channelID) {
+		return errors.New("channel is banned from starboard")
+	}
+
+	if len(guild.ActiveBoards()) == 0 {
+		return errors.New("starboard channel isn't set, please use ``set starboard`` first")
+	}
+
+	if len(args) > 1 {
+		var err error
+		since, err = utils.ParseSince(args[1])
+		if err != nil {
+			return err
+		}
+	}
+
+	finish, err := startJob(m.GuildID, "scan")
+	if err != nil {
+		return err
+	}
+	defer finish()
+
+	progress, err := newProgressMessage(s, m.ChannelID, "Scanning channel history")
+	if err != nil {
+		return err
+	}
+
+	var (
+		before          = ""
+		scanned, posted int
+		oldest          time.Time
+		done            bool
+		status          = func() string {
+			str := fmt.Sprintf("**Channel:** <#%v>\n**Messages scanned:** %v\n**Messages posted:** %v", channelID, scanned, posted)
+			if !oldest.IsZero() {
+				str += fmt.Sprintf("\n**Reached:** %v", oldest.Format("2006-01-02"))
+			}
+			return str
+		}
+	)
+
+	for !done {
+		messages, err := s.ChannelMessages(channelID, 100, before, "", "")
+		if err != nil {
+			progress.Finish("❎ Scan failed", status())
+			return err
+		}
+
+		if len(messages) == 0 {
+			break
+		}
+
+		for _, msg := range messages {
+			msg.GuildID = m.GuildID
+			if t, err := msg.Timestamp.Parse(); err == nil {
+				if !since.IsZero() && t.Before(since) {
+					done = true
+					break
+				}
+				oldest = t
+			}
+
+			scanned++
+			if !canStarboard(s, guild, msg) {
+				continue
+			}
+
+			for _, board := range guild.ActiveBoards() {
+				react := utils.FindReact(msg, board.StarEmote)
+				if react == nil || react.Count < guild.BoardStarsRequired(board, msg.ChannelID) {
+					continue
+				}
+
+				ok, err := Starboard.Backfill(s, m.GuildID, msg, board)
+				if err != nil {
+					logrus.Warnf("scan() -> Backfill(): %v. Channel ID: %v, Message ID: %v", err, msg.ChannelID, msg.ID)
+					continue
+				}
+
+				if ok {
+					posted++
+					//Be gentle with starboard channel's rate limits.
+					time.Sleep(time.Second)
+				}
+			}
+		}
+
+		before = messages[len(messages)-1].ID
+		progress.Update(status(), false)
+	}
+
+	progress.Finish("✅ Scan complete", status())
+	return nil
+}
+
+//RepairReport summarizes a repair of guild's starboard entries.
+type RepairReport struct {
+	Total            int
+	Checked          int
+	Updated          int
+	OriginalMissing  int
+	StarboardMissing int
+	Skipped          int
+	Failed           int
+}
+
+func (r *RepairReport) String() string {
+	return fmt.Sprintf("**Checked:** %v/%v\n**Fixed star counts:** %v\n**Removed (original deleted):** %v\n**Removed (starboard deleted):** %v\n**Skipped:** %v\n**Failed:** %v", r.Checked, r.Total, r.Updated, r.OriginalMissing, r.StarboardMissing, r.Skipped, r.Failed)
+}
+
+//RepairGuild repairs all starboard entries of a guild. If progress isn't nil it's called after every entry.
+func RepairGuild(s *discordgo.Session, guildID string, progress func(*RepairReport)) (*RepairReport, error) {
+	finish, err := startJob(guildID, "repair")
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+
+	entries, err := database.MessagesByGuild(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RepairReport{Total: len(entries)}
+	for _, entry := range entries {
+		res, err := Starboard.Repair(s, guildID, entry)
+		report.Checked++
+		if err != nil {
+			logrus.Warnf("RepairGuild() -> Repair(): %v. Channel ID: %v, Message ID: %v", err, entry.Original.ChannelID, entry.Original.MessageID)
+			report.Failed++
+		} else {
+			switch res {
+			case RepairUpdated:
+				report.Updated++
+			case RepairOriginalMissing:
+				report.OriginalMissing++
+			case RepairStarboardMissing:
+				report.StarboardMissing++
+			case RepairSkipped:
+				report.Skipped++
+			}
+		}
+
+		if progress != nil {
+			progress(report)
+		}
+	}
+
+	return report, nil
+}
+
+func repair(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if err := isAdmin(s, m); err != nil {
+		return err
+	}
+
+	progress, err := newProgressMessage(s, m.ChannelID, "Repairing starboard")
+	if err != nil {
+		return err
+	}
+
+	report, err := RepairGuild(s, m.GuildID, func(r *RepairReport) {
+		progress.Update(r.String(), false)
+	})
+	if err != nil {
+		progress.Finish("❎ Repair failed", err.Error())
+		return err
+	}
+
+	progress.Finish("✅ Repair complete", report.String())
+	return nil
+}
+
+func importStarboard(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if err := isAdmin(s, m); err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return utils.ErrNotEnoughArguments
+	}
+
+	var (
+		guild     = database.GuildCache[m.GuildID]
+		channelID = strings.Trim(args[0], "<#>")
+		board     = guild.DefaultBoard()
+		parser    importParser
+	)
+
+	if !utils.IsValidChannel(s, m.GuildID, channelID) {
+		return fmt.Errorf("Unable to get channel <#%v>. Please make sure Eugen has permissions to see the channel.", channelID)
+	}
+
+	for _, arg := range args[1:] {
+		if p := findImportParser(arg); p != nil {
+			parser = p
+			continue
+		}
+
+		b := guild.FindBoard(arg)
+		if b == nil {
+			return fmt.Errorf("unknown board or parser %v", arg)
+		}
+		board = b
+	}
+
+	finish, err := startJob(m.GuildID, "import")
+	if err != nil {
+		return err
+	}
+	defer finish()
+
+	progress, err := newProgressMessage(s, m.ChannelID, "Importing starboard")
+	if err != nil {
+		return err
+	}
+
+	var (
+		before                                  = ""
+		scanned, imported, duplicates, unparsed int
+		seen                                    = make(map[database.MessagePair]bool)
+		batch                                   = make([]interface{}, 0, 100)
+		status                                  = func() string {
+			return fmt.Sprintf("**Channel:** <#%v>\n**Board:** %v\n**Posts scanned:** %v\n**Imported:** %v\n**Already on starboard:** %v\n**Not recognized:** %v", channelID, board.DisplayName(), scanned, imported, duplicates, unparsed)
+		}
+		flush = func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+
+			err := database.InsertManyMessages(batch)
+			if err != nil {
+				return err
+			}
+
+			imported += len(batch)
+			batch = make([]interface{}, 0, 100)
+			return nil
+		}
+	)
+
+	for {
+		messages, err := s.ChannelMessages(channelID, 100, before, "", "")
+		if err != nil {
+			progress.Finish("❎ Import failed", status())
+			return err
+		}
+
+		if len(messages) == 0 {
+			break
+		}
+
+		for _, msg := range messages {
+			if msg.Author == nil || !msg.Author.Bot {
+				continue
+			}
+			scanned++
+
+			var (
+				entry *importedEntry
+				ok    bool
+			)
+			if parser != nil {
+				entry, ok = parser.Parse(msg)
+			} else {
+				entry, ok = parseImport(msg)
+			}
+
+			if !ok {
+				unparsed++
+				continue
+			}
+
+			if seen[*entry.Original] {
+				duplicates++
+				continue
+			}
+			seen[*entry.Original] = true
+
+			existing, err := database.Repost(entry.Original.ChannelID, entry.Original.MessageID, board.Name)
+			if err != nil {
+				progress.Finish("❎ Import failed", status())
+				return err
+			}
+
+			if existing != nil {
+				duplicates++
+				continue
+			}
+
+			starboard := database.NewPair(msg.ChannelID, msg.ID)
+			record := database.NewMessage(entry.Original, &starboard, m.GuildID, board.Name)
+			record.Stars = entry.Stars
+			if t, err := msg.Timestamp.Parse(); err == nil {
+				record.CreatedAt = t
+			}
+
+			if original, err := s.ChannelMessage(entry.Original.ChannelID, entry.Original.MessageID); err == nil && original.Author != nil {
+				record.AuthorID = original.Author.ID
+				record.Snapshot = database.NewSnapshot(original)
+			}
+
+			batch = append(batch, *record)
+		}
+
+		if len(batch) >= 100 {
+			if err := flush(); err != nil {
+				progress.Finish("❎ Import failed", status())
+				return err
+			}
+		}
+
+		before = messages[len(messages)-1].ID
+		progress.Update(status(), false)
+	}
+
+	if err := flush(); err != nil {
+		progress.Finish("❎ Import failed", status())
+		return err
+	}
+
+	progress.Finish("✅ Import complete", status())
+	return nil
+}
@@ -0,0 +1,276 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+)
+
+func init() {
+	moderationGroup := CommandGroup{
+		Name:        "moderation",
+		Description: "Starboard moderation commands.",
+		NSFW:        false,
+		Commands:    make(map[string]Command),
+		IsVisible:   true,
+	}
+
+	starCommand := newCommand("star", "Posts a message to starboard regardless of its star count. Use ``{prefix}help star`` for more info.").setExec(star).setAliases("forcestar").setGuildOnly(true)
+	starCommand.Help.ExtendedHelp = []*discordgo.MessageEmbedField{
+		{
+			Name:  "Usage",
+			Value: "{prefix}star ``<message link>`` ``[board]``",
+		},
+		{
+			Name:  "Forced entries",
+			Value: "Forced entries keep updating their star count but aren't removed when they lose stars. Use ``{prefix}unstar`` to remove them.",
+		},
+	}
+	unstarCommand := newCommand("unstar", "Takes a message down from starboard. Usage: ``{prefix}unstar <message link> [board]``").setExec(unstar).setGuildOnly(true)
+	freezeCommand := newCommand("freeze", "Locks starboard entry's star count, reactions no longer edit or remove it. Usage: ``{prefix}freeze <message link> [board]``").setExec(freeze).setGuildOnly(true)
+	unfreezeCommand := newCommand("unfreeze", "Unlocks a frozen starboard entry. Usage: ``{prefix}unfreeze <message link> [board]``").setExec(unfreeze).setGuildOnly(true)
+	promoteCommand := newCommand("promote", "Cross-posts a starboard entry to the hall of fame regardless of its star count. Usage: ``{prefix}promote <message link> [board]``").setExec(promote).setGuildOnly(true)
+	demoteCommand := newCommand("demote", "Takes an entry down from the hall of fame, it won't be promoted again automatically. Usage: ``{prefix}demote <message link> [board]``").setExec(demote).setGuildOnly(true)
+	auditCommand := newCommand("audit", "Shows latest audit log entries. Use ``{prefix}help audit`` for more info.").setExec(audit).setAliases("auditlog").setGuildOnly(true)
+	auditCommand.Help.ExtendedHelp = []*discordgo.MessageEmbedField{
+		{
+			Name:  "Usage",
+			Value: "{prefix}audit ``[action]`` ``[@user]``. Shows 10 latest entries, optionally only of an action or triggered by a user.",
+		},
+		{
+			Name:  "Actions",
+			Value: "create, edit, remove, delete, star, unstar, freeze, unfreeze, promote, demote, settings, setup, ban, unban, blacklist, unblacklist",
+		},
+		{
+			Name:  "Audit channel",
+			Value: "Use ``{prefix}set audit <channel>`` to report every action to a channel as it happens.",
+		},
+	}
+
+	moderationGroup.addCommand(starCommand)
+	moderationGroup.addCommand(unstarCommand)
+	moderationGroup.addCommand(freezeCommand)
+	moderationGroup.addCommand(unfreezeCommand)
+	moderationGroup.addCommand(promoteCommand)
+	moderationGroup.addCommand(demoteCommand)
+	moderationGroup.addCommand(auditCommand)
+	CommandGroups["moderation"] = moderationGroup
+}
+
+//isModerator checks if a member can manage messages on a server.
+func isModerator(s *discordgo.Session, m *discordgo.MessageCreate) error {
+	ok, err := utils.MemberHasPermission(s, m.GuildID, m.Author.ID, discordgo.PermissionAdministrator|discordgo.PermissionManageServer|discordgo.PermissionManageMessages)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return utils.ErrNoPermission
+	}
+
+	return nil
+}
+
+//linkedMessage returns a message from a jump link in the first argument and a board from an optional second argument.
+func linkedMessage(s *discordgo.Session, m *discordgo.MessageCreate, args []string) (*discordgo.Message, *database.Board, error) {
+	if len(args) == 0 {
+		return nil, nil, utils.ErrNotEnoughArguments
+	}
+
+	guildID, channelID, messageID, ok := utils.ParseMessageLink(args[0])
+	if !ok {
+		return nil, nil, errors.New("first argument should be a message link")
+	}
+
+	if guildID != m.GuildID {
+		return nil, nil, errors.New("message is from a foreign server")
+	}
+
+	guild := database.GuildCache[m.GuildID]
+	board := guild.DefaultBoard()
+	if len(args) > 1 {
+		board = guild.FindBoard(args[1])
+		if board == nil {
+			return nil, nil, fmt.Errorf("board %v doesn't exist", args[1])
+		}
+	}
+
+	if board.Channel == "" {
+		return nil, nil, fmt.Errorf("%v board doesn't have a starboard channel", board.DisplayName())
+	}
+
+	msg, err := s.ChannelMessage(channelID, messageID)
+	if err != nil {
+		return nil, nil, err
+	}
+	msg.GuildID = guildID
+
+	return msg, board, nil
+}
+
+func star(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if err := isModerator(s, m); err != nil {
+		return err
+	}
+
+	msg, board, err := linkedMessage(s, m, args)
+	if err != nil {
+		return err
+	}
+
+	err = Starboard.Star(s, m.GuildID, m.Author.ID, msg, board)
+	if err != nil {
+		return err
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully posted the message to ``%v`` board", board.DisplayName()))
+	return nil
+}
+
+func unstar(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if err := isModerator(s, m); err != nil {
+		return err
+	}
+
+	msg, board, err := linkedMessage(s, m, args)
+	if err != nil {
+		return err
+	}
+
+	err = Starboard.Unstar(s, m.GuildID, m.Author.ID, msg, board)
+	if err != nil {
+		return err
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully removed the message from ``%v`` board", board.DisplayName()))
+	return nil
+}
+
+func promote(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if err := isModerator(s, m); err != nil {
+		return err
+	}
+
+	msg, board, err := linkedMessage(s, m, args)
+	if err != nil {
+		return err
+	}
+
+	err = Starboard.Promote(s, m.GuildID, m.Author.ID, msg, board)
+	if err != nil {
+		return err
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully promoted the entry of ``%v`` board to the hall of fame", board.DisplayName()))
+	return nil
+}
+
+func demote(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if err := isModerator(s, m); err != nil {
+		return err
+	}
+
+	msg, board, err := linkedMessage(s, m, args)
+	if err != nil {
+		return err
+	}
+
+	err = Starboard.Demote(s, m.GuildID, m.Author.ID, msg, board)
+	if err != nil {
+		return err
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully demoted the entry of ``%v`` board from the hall of fame", board.DisplayName()))
+	return nil
+}
+
+func freeze(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	return setFrozen(s, m, args, true)
+}
+
+func unfreeze(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	return setFrozen(s, m, args, false)
+}
+
+func setFrozen(s *discordgo.Session, m *discordgo.MessageCreate, args []string, frozen bool) error {
+	if err := isModerator(s, m); err != nil {
+		return err
+	}
+
+	msg, board, err := linkedMessage(s, m, args)
+	if err != nil {
+		return err
+	}
+
+	if frozen {
+		err = Starboard.Freeze(s, m.GuildID, m.Author.ID, msg, board)
+	} else {
+		err = Starboard.Unfreeze(s, m.GuildID, m.Author.ID, msg, board)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if frozen {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully froze the entry on ``%v`` board", board.DisplayName()))
+	} else {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully unfroze the entry on ``%v`` board", board.DisplayName()))
+	}
+	return nil
+}
+
+func audit(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if err := isModerator(s, m); err != nil {
+		return err
+	}
+
+	var action, userID string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "<@") {
+			userID = strings.Trim(arg, "<@!>")
+		} else {
+			action = strings.ToLower(arg)
+		}
+	}
+
+	entries, err := database.AuditLog(m.GuildID, action, userID, 10)
+	if err != nil {
+		return err
+	}
+
+	embed := utils.BaseEmbed(s)
+	embed.Title = "Audit log"
+	if len(entries) == 0 {
+		embed.Description = "No entries found."
+	}
+
+	for _, entry := range entries {
+		user := "Eugen"
+		if entry.UserID != "" {
+			user = fmt.Sprintf("<@%v>", entry.UserID)
+		}
+
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("**By:** %v", user))
+		if entry.IsEntryAction() {
+			sb.WriteString(fmt.Sprintf(" | [Original](%v) | **Stars:** %v → %v", entry.Original.Link(m.GuildID), entry.Before, entry.After))
+		}
+
+		if entry.Details != "" {
+			sb.WriteString("\n" + entry.Details)
+		}
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("%v | %v", entry.Action, entry.CreatedAt.Format("2006-01-02 15:04")),
+			Value: sb.String(),
+		})
+	}
+
+	s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	return nil
+}
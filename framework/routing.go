@@ -0,0 +1,210 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+)
+
+func init() {
+	routingGroup := CommandGroup{
+		Name:        "routing",
+		Description: "Rules routing starboard entries to different channels.",
+		NSFW:        false,
+		Commands:    make(map[string]Command),
+		IsVisible:   true,
+	}
+
+	routeCommand := newCommand("route", "Lists, adds, removes and tests routing rules. Use ``{prefix}help route`` for more info.").setExec(route).setAliases("routes", "routing").setGuildOnly(true)
+	routeCommand.Help.ExtendedHelp = []*discordgo.MessageEmbedField{
+		{
+			Name:  "Usage",
+			Value: "{prefix}route ``[add|remove|test]`` ``[arguments]``. Rules are checked in order, the first matching rule picks a channel. Without matching rules board's channel is used, or its NSFW channel for NSFW channels.",
+		},
+		{
+			Name:  "add",
+			Value: "{prefix}route add ``<channel|category|thread>`` ``<channel>`` ``<destination>`` ``[board]``\n{prefix}route add ``nsfw`` ``<destination>`` ``[board]``\n``thread`` matches threads of a channel. Board defaults to the default board.",
+		},
+		{
+			Name:  "remove",
+			Value: "{prefix}route remove ``<number>``. Removes a rule by its number in ``{prefix}route`` list.",
+		},
+		{
+			Name:  "test",
+			Value: "{prefix}route test ``<channel>`` ``[board]``. Shows where entries from a channel would be posted to.",
+		},
+	}
+
+	routingGroup.addCommand(routeCommand)
+	CommandGroups["routing"] = routingGroup
+}
+
+func route(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if len(args) == 0 || args[0] == "list" {
+		return listRoutes(s, m)
+	}
+
+	if err := isAdmin(s, m); err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "add":
+		return addRoute(s, m, args[1:])
+	case "remove", "delete":
+		return removeRoute(s, m, args[1:])
+	case "test":
+		return testRoute(s, m, args[1:])
+	default:
+		return errors.New("unknown subcommand " + args[0])
+	}
+}
+
+func listRoutes(s *discordgo.Session, m *discordgo.MessageCreate) error {
+	guild := database.GuildCache[m.GuildID]
+
+	embed := utils.BaseEmbed(s)
+	embed.Title = "Routing rules"
+	embed.Description = guild.RoutesToString()
+
+	s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	return nil
+}
+
+//guildChannel returns a channel of the guild from a channel mention or ID.
+func guildChannel(s *discordgo.Session, guildID, arg string) (*discordgo.Channel, error) {
+	channelID := strings.Trim(arg, "<#>")
+	ch, err := s.Channel(channelID)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to get channel <#%v>. Please make sure Eugen has permissions to see the channel.", channelID)
+	}
+
+	if ch.GuildID != guildID {
+		return nil, errors.New("channel is from a foreign server")
+	}
+
+	return ch, nil
+}
+
+func addRoute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if len(args) < 2 {
+		return utils.ErrNotEnoughArguments
+	}
+
+	var (
+		guild    = database.GuildCache[m.GuildID]
+		ruleType = strings.ToLower(args[0])
+		value    string
+	)
+
+	if !database.IsValidRouteType(ruleType) {
+		return fmt.Errorf("unknown rule type %v, it should be one of channel, category, nsfw or thread", args[0])
+	}
+
+	args = args[1:]
+	if ruleType != database.RouteNSFW {
+		if len(args) < 2 {
+			return utils.ErrNotEnoughArguments
+		}
+
+		ch, err := guildChannel(s, m.GuildID, args[0])
+		if err != nil {
+			return err
+		}
+
+		if ruleType == database.RouteCategory && ch.Type != discordgo.ChannelTypeGuildCategory {
+			return fmt.Errorf("<#%v> is not a category", ch.ID)
+		}
+
+		value = ch.ID
+		args = args[1:]
+	}
+
+	destination, err := guildChannel(s, m.GuildID, args[0])
+	if err != nil {
+		return err
+	}
+
+	b := guild.DefaultBoard()
+	if len(args) > 1 {
+		b = guild.FindBoard(args[1])
+		if b == nil {
+			return fmt.Errorf("board %v doesn't exist", args[1])
+		}
+	}
+
+	rule := database.NewRouteRule(ruleType, value, b.Name, destination.ID)
+	err = database.AddRouteRule(m.GuildID, rule)
+	if err != nil {
+		return err
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully added routing rule: %v", rule))
+	return nil
+}
+
+func removeRoute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if len(args) == 0 {
+		return utils.ErrNotEnoughArguments
+	}
+
+	num, err := strconv.Atoi(args[0])
+	if err != nil {
+		return utils.ErrParsingArgument
+	}
+
+	err = database.RemoveRouteRule(m.GuildID, num-1)
+	if err != nil {
+		return err
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully removed routing rule %v", num))
+	return nil
+}
+
+func testRoute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if len(args) == 0 {
+		return utils.ErrNotEnoughArguments
+	}
+
+	guild := database.GuildCache[m.GuildID]
+	ch, err := guildChannel(s, m.GuildID, args[0])
+	if err != nil {
+		return err
+	}
+
+	b := guild.DefaultBoard()
+	if len(args) > 1 {
+		b = guild.FindBoard(args[1])
+		if b == nil {
+			return fmt.Errorf("board %v doesn't exist", args[1])
+		}
+	}
+
+	ctx, err := utils.NewRouteContext(s, ch)
+	if err != nil {
+		return err
+	}
+
+	destination, rule := guild.Route(b, ctx)
+	reason := "no matching rules, board's default channel"
+	if rule != nil {
+		reason = "rule " + rule.String()
+	}
+
+	if ctx.NSFW && !b.NSFW {
+		reason += "\n⚠️ Board doesn't accept messages from NSFW channels."
+	}
+
+	embed := utils.BaseEmbed(s)
+	embed.Title = "Routing test"
+	embed.Description = fmt.Sprintf("**Channel:** <#%v>\n**Board:** %v\n**Destination:** %v\n**Reason:** %v", ch.ID, b.DisplayName(), utils.FormatChannel(destination), reason)
+
+	s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	return nil
+}
@@ -0,0 +1,184 @@
+package framework
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+)
+
+//searchSnippet is a maximum length of an entry's content shown in search results.
+const searchSnippet = 80
+
+func init() {
+	searchGroup := CommandGroup{
+		Name:        "search",
+		Description: "Search through starboard entries.",
+		NSFW:        false,
+		Commands:    make(map[string]Command),
+		IsVisible:   true,
+	}
+
+	searchCommand := newCommand("search", "Searches starboard entries by content, author names and embed titles. Use ``{prefix}help search`` for more info.").setExec(search).setAliases("find").setGuildOnly(true)
+	searchCommand.Help.ExtendedHelp = []*discordgo.MessageEmbedField{
+		{
+			Name:  "Usage",
+			Value: "{prefix}search ``[query]`` ``[filters]``",
+		},
+		{
+			Name:  "Query",
+			Value: "Words to look for. Put a phrase in quotes to match it exactly, prefix a word with ``-`` to exclude it. Only entries with stored snapshots are matched.",
+		},
+		{
+			Name:  "Filters",
+			Value: "``from:@user``, ``in:#channel``, ``before:<time>``, ``after:<time>`` and ``stars>10``, ``stars>=10``, ``stars<10``, ``stars<=10`` or ``stars=10``. Time is either a date like ``2021-04-01`` or a relative time like ``2w``, ``3m`` or ``1y``.",
+		},
+		{
+			Name:  "Example",
+			Value: "{prefix}search cat in:#memes after:2021-03-01 before:2021-06-01 stars>10",
+		},
+		{
+			Name:  "NSFW",
+			Value: "Entries from NSFW channels are only listed in NSFW channels.",
+		},
+	}
+
+	searchGroup.addCommand(searchCommand)
+	CommandGroups["search"] = searchGroup
+}
+
+func search(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if len(args) == 0 {
+		return utils.ErrNotEnoughArguments
+	}
+
+	var (
+		filter = &database.SearchFilter{GuildID: m.GuildID}
+		words  = make([]string, 0)
+		err    error
+	)
+
+	for _, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, "from:"):
+			filter.AuthorID = strings.Trim(strings.TrimPrefix(arg, "from:"), "<@!>")
+		case strings.HasPrefix(arg, "in:"):
+			ch, err := guildChannel(s, m.GuildID, strings.TrimPrefix(arg, "in:"))
+			if err != nil {
+				return err
+			}
+			filter.ChannelID = ch.ID
+		case strings.HasPrefix(arg, "before:"):
+			filter.Before, err = utils.ParseSince(strings.TrimPrefix(arg, "before:"))
+			if err != nil {
+				return err
+			}
+		case strings.HasPrefix(arg, "after:"):
+			filter.After, err = utils.ParseSince(strings.TrimPrefix(arg, "after:"))
+			if err != nil {
+				return err
+			}
+		case len(arg) > 5 && strings.HasPrefix(arg, "stars") && strings.ContainsAny(arg[5:6], "<>=:"):
+			err := parseStarsFilter(filter, strings.TrimPrefix(arg, "stars"))
+			if err != nil {
+				return err
+			}
+		default:
+			words = append(words, arg)
+		}
+	}
+
+	entries, err := newEntryFilter(s, m.ChannelID)
+	if err != nil {
+		return err
+	}
+
+	filter.Query = strings.Join(words, " ")
+	found, err := database.SearchMessages(filter, leaderboardLimit)
+	if err != nil {
+		return err
+	}
+
+	messages := make([]*database.Message, 0, len(found))
+	for _, msg := range found {
+		if entries.allowed(msg) {
+			messages = append(messages, msg)
+		}
+	}
+
+	if len(messages) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "No starboard entries found.")
+		return nil
+	}
+
+	var (
+		loc   = database.GuildCache[m.GuildID].Location()
+		lines = make([]string, 0, len(messages))
+	)
+
+	for ind, msg := range messages {
+		author := "unknown"
+		if msg.AuthorID != "" {
+			author = fmt.Sprintf("<@%v>", msg.AuthorID)
+		}
+
+		line := fmt.Sprintf("**%v.** ⭐ %v | %v in <#%v> | %v | [Jump](%v) | [Starboard](%v)", ind+1, msg.Stars, author, msg.Original.ChannelID, msg.CreatedAt.In(loc).Format("Jan 2, 2006"), msg.Original.Link(msg.GuildID), msg.Starboard.Link(msg.GuildID))
+		if msg.Snapshot != nil && msg.Snapshot.Content != "" {
+			line += "\n> " + snippet(msg.Snapshot.Content)
+		}
+
+		lines = append(lines, line)
+	}
+
+	return utils.NewPaginator(s, m.ChannelID, m.Author.ID, leaderboardPages(s, fmt.Sprintf("Search results (%v)", len(messages)), lines)).Run()
+}
+
+//parseStarsFilter parses a star count comparison like >10, >=10, <10, <=10 or =10. Comparisons no entry can match are rejected.
+func parseStarsFilter(filter *database.SearchFilter, arg string) error {
+	var (
+		op  = strings.TrimRight(arg, "0123456789")
+		num = strings.TrimPrefix(arg, op)
+	)
+
+	stars, err := strconv.Atoi(num)
+	if err != nil || stars < 0 {
+		return fmt.Errorf("unable to parse star filter stars%v", arg)
+	}
+
+	switch op {
+	case ">":
+		filter.MinStars = stars + 1
+	case ">=":
+		filter.MinStars = stars
+	case "<":
+		filter.MaxStars = stars - 1
+	case "<=":
+		filter.MaxStars = stars
+	case "=", ":":
+		filter.MinStars = stars
+		filter.MaxStars = stars
+	default:
+		return fmt.Errorf("unknown star filter stars%v, use >, >=, <, <= or =", arg)
+	}
+
+	//Zero maximum means there's no maximum, but every entry has at least a star.
+	if op != ">" && op != ">=" && filter.MaxStars < 1 {
+		return fmt.Errorf("star filter stars%v doesn't match any entries", arg)
+	}
+
+	return nil
+}
+
+//snippet flattens an entry's content to a single line and cuts it to searchSnippet characters. Spoilers are hidden first, so a cut can't expose them.
+func snippet(content string) string {
+	content = utils.SpoilerRegex.ReplaceAllString(content, "[spoiler]")
+	content = strings.Join(strings.Fields(content), " ")
+	if runes := []rune(content); len(runes) > searchSnippet {
+		content = string(runes[:searchSnippet]) + "…"
+	}
+
+	return content
+}
@@ -0,0 +1,70 @@
+package framework
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/VTGare/Eugen/database"
+)
+
+func TestParseStarsFilter(t *testing.T) {
+	tests := []struct {
+		arg     string
+		min     int
+		max     int
+		wantErr bool
+	}{
+		{arg: ">10", min: 11},
+		{arg: ">=10", min: 10},
+		{arg: "<10", max: 9},
+		{arg: "<=10", max: 10},
+		{arg: "=10", min: 10, max: 10},
+		{arg: ":10", min: 10, max: 10},
+		{arg: ">0", min: 1},
+		{arg: "<1", wantErr: true},
+		{arg: "<=0", wantErr: true},
+		{arg: "=0", wantErr: true},
+		{arg: "!=10", wantErr: true},
+		{arg: ">ten", wantErr: true},
+		{arg: ">", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			filter := &database.SearchFilter{}
+			err := parseStarsFilter(filter, tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseStarsFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err == nil && (filter.MinStars != tt.min || filter.MaxStars != tt.max) {
+				t.Errorf("parseStarsFilter() = %v-%v, want %v-%v", filter.MinStars, filter.MaxStars, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	long := strings.Repeat("a", searchSnippet)
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"short", "hello world", "hello world"},
+		{"multiline", "hello\n\nworld", "hello world"},
+		{"spoiler", "the ending ||is sad||", "the ending [spoiler]"},
+		{"long", long + "bbb", long + "…"},
+		{"cut spoiler", long + " ||secret||", long + "…"},
+		{"spoiler before cut", "||" + long + "||", "[spoiler]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := snippet(tt.content); got != tt.want {
+				t.Errorf("snippet() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
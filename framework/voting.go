@@ -0,0 +1,227 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+)
+
+func init() {
+	votingGroup := CommandGroup{
+		Name:        "voting",
+		Description: "Role restricted and weighted voting.",
+		NSFW:        false,
+		Commands:    make(map[string]Command),
+		IsVisible:   true,
+	}
+
+	votersCommand := newCommand("voters", "Restricts voting to members with certain roles. Use ``{prefix}help voters`` for more info.").setExec(voters).setAliases("voterroles").setGuildOnly(true)
+	votersCommand.Help.ExtendedHelp = []*discordgo.MessageEmbedField{
+		{
+			Name:  "Usage",
+			Value: "{prefix}voters ``[add|remove]`` ``<role>``",
+		},
+		{
+			Name:  "Voter roles",
+			Value: "If any voter roles are set, only reactions of members with one of them count. Everyone can vote otherwise. Members of weighted roles can always vote.",
+		},
+	}
+
+	weightCommand := newCommand("weight", "Makes votes of a role count as several stars. Use ``{prefix}help weight`` for more info.").setExec(weight).setAliases("roleweight").setGuildOnly(true)
+	weightCommand.Help.ExtendedHelp = []*discordgo.MessageEmbedField{
+		{
+			Name:  "Usage",
+			Value: "{prefix}weight ``<role>`` ``<stars|reset>``",
+		},
+		{
+			Name:  "Weighted score",
+			Value: "Member's vote is worth the highest weight of their roles. Starboard footer shows the weighted score and raw reaction count.",
+		},
+	}
+
+	starbanCommand := newCommand("starban", "Stops users' stars from counting. Usage: ``{prefix}starban <users>``. Use ``{prefix}set removebanned true`` to also remove their reactions.").setExec(starban).setGuildOnly(true)
+	unstarbanCommand := newCommand("unstarban", "Lets banned voters star messages again. Usage: ``{prefix}unstarban <users>``").setExec(unstarban).setGuildOnly(true)
+
+	votingGroup.addCommand(votersCommand)
+	votingGroup.addCommand(weightCommand)
+	votingGroup.addCommand(starbanCommand)
+	votingGroup.addCommand(unstarbanCommand)
+	CommandGroups["voting"] = votingGroup
+}
+
+//guildRole returns a role of the guild from a role mention or ID.
+func guildRole(s *discordgo.Session, guildID, arg string) (*discordgo.Role, error) {
+	roleID := strings.Trim(arg, "<@&>")
+	if role, err := s.State.Role(guildID, roleID); err == nil {
+		return role, nil
+	}
+
+	roles, err := s.GuildRoles(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, role := range roles {
+		if role.ID == roleID {
+			return role, nil
+		}
+	}
+
+	return nil, fmt.Errorf("role %v doesn't exist", arg)
+}
+
+func voters(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if len(args) == 0 {
+		guild := database.GuildCache[m.GuildID]
+
+		embed := utils.BaseEmbed(s)
+		embed.Title = "Voting"
+		embed.Fields = []*discordgo.MessageEmbedField{
+			{
+				Name:  "Voter roles",
+				Value: guild.VoterRolesToString(),
+			},
+			{
+				Name:  "Role weights",
+				Value: guild.RoleWeightsToString(),
+			},
+			{
+				Name:  "Banned voters",
+				Value: guild.BannedVotersToString(),
+			},
+		}
+
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return nil
+	}
+
+	if err := isAdmin(s, m); err != nil {
+		return err
+	}
+
+	if len(args) < 2 {
+		return utils.ErrNotEnoughArguments
+	}
+
+	role, err := guildRole(s, m.GuildID, args[1])
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "add":
+		err = database.AddVoterRole(m.GuildID, role.ID)
+		if err != nil {
+			return err
+		}
+
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully added ``%v`` to voter roles", role.Name))
+	case "remove", "delete":
+		err = database.RemoveVoterRole(m.GuildID, role.ID)
+		if err != nil {
+			return err
+		}
+
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully removed ``%v`` from voter roles", role.Name))
+	default:
+		return errors.New("unknown subcommand " + args[0])
+	}
+
+	return nil
+}
+
+func weight(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if err := isAdmin(s, m); err != nil {
+		return err
+	}
+
+	if len(args) < 2 {
+		return utils.ErrNotEnoughArguments
+	}
+
+	role, err := guildRole(s, m.GuildID, args[0])
+	if err != nil {
+		return err
+	}
+
+	if args[1] == "reset" || args[1] == "default" {
+		err := database.RemoveRoleWeight(m.GuildID, role.ID)
+		if err != nil {
+			return err
+		}
+
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully reset ``%v`` weight", role.Name))
+		return nil
+	}
+
+	stars, err := strconv.Atoi(args[1])
+	if err != nil {
+		return utils.ErrParsingArgument
+	}
+
+	if stars < 1 {
+		return fmt.Errorf("Weight should be >= 1, provided weight is %v", stars)
+	}
+
+	err = database.SetRoleWeight(m.GuildID, role.ID, stars)
+	if err != nil {
+		return err
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully set ``%v`` weight to ``%v`` stars", role.Name, stars))
+	return nil
+}
+
+func starban(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	return setVoterBans(s, m, args, true)
+}
+
+func unstarban(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	return setVoterBans(s, m, args, false)
+}
+
+func setVoterBans(s *discordgo.Session, m *discordgo.MessageCreate, args []string, banned bool) error {
+	if err := isAdmin(s, m); err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return utils.ErrNotEnoughArguments
+	}
+
+	users := make([]string, 0, len(args))
+	for _, arg := range args {
+		user, err := s.User(strings.Trim(arg, "<@!>"))
+		if err != nil {
+			return fmt.Errorf("user %v doesn't exist", arg)
+		}
+
+		if banned {
+			err = database.BanVoter(m.GuildID, user.ID)
+		} else {
+			err = database.UnbanVoter(m.GuildID, user.ID)
+		}
+		if err != nil {
+			return err
+		}
+
+		users = append(users, fmt.Sprintf("<@%v>", user.ID))
+	}
+
+	embed := utils.BaseEmbed(s)
+	if banned {
+		embed.Title = "✅ Successfully banned voters"
+		embed.Description = fmt.Sprintf("Stars of these users no longer count:\n%v", strings.Join(users, " | "))
+	} else {
+		embed.Title = "✅ Successfully unbanned voters"
+		embed.Description = fmt.Sprintf("Stars of these users count again:\n%v", strings.Join(users, " | "))
+	}
+
+	s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	return nil
+}
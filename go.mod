@@ -5,8 +5,8 @@ go 1.14
 require (
 	github.com/VTGare/embeds v0.1.0
 	github.com/bwmarrin/discordgo v0.22.0
-	github.com/jasonlvhit/gocron v0.0.1
 	github.com/sirupsen/logrus v1.7.0
 	go.mongodb.org/mongo-driver v1.4.4
+	golang.org/x/net v0.0.0-20200226121028-0de0cce0169b // indirect
 	mvdan.cc/xurls/v2 v2.2.0
 )
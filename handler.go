@@ -0,0 +1,69 @@
+package main
+
+import (
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/framework"
+	"github.com/bwmarrin/discordgo"
+)
+
+//starboardHandler implements framework.StarboardHandler by pushing moderator actions to the starboard queue.
+type starboardHandler struct{}
+
+func (starboardHandler) Star(s *discordgo.Session, guildID, userID string, msg *discordgo.Message, board *database.Board) error {
+	se := newStarboardEventAction(s, guildID, msg, board, actionStar)
+	se.userID = userID
+	return se.await()
+}
+
+func (starboardHandler) Unstar(s *discordgo.Session, guildID, userID string, msg *discordgo.Message, board *database.Board) error {
+	se := newStarboardEventAction(s, guildID, msg, board, actionUnstar)
+	se.userID = userID
+	return se.await()
+}
+
+func (starboardHandler) Promote(s *discordgo.Session, guildID, userID string, msg *discordgo.Message, board *database.Board) error {
+	se := newStarboardEventAction(s, guildID, msg, board, actionPromote)
+	se.userID = userID
+	return se.await()
+}
+
+func (starboardHandler) Demote(s *discordgo.Session, guildID, userID string, msg *discordgo.Message, board *database.Board) error {
+	se := newStarboardEventAction(s, guildID, msg, board, actionDemote)
+	se.userID = userID
+	return se.await()
+}
+
+func (starboardHandler) Freeze(s *discordgo.Session, guildID, userID string, msg *discordgo.Message, board *database.Board) error {
+	se := newStarboardEventAction(s, guildID, msg, board, actionFreeze)
+	se.userID = userID
+	return se.await()
+}
+
+func (starboardHandler) Unfreeze(s *discordgo.Session, guildID, userID string, msg *discordgo.Message, board *database.Board) error {
+	se := newStarboardEventAction(s, guildID, msg, board, actionUnfreeze)
+	se.userID = userID
+	return se.await()
+}
+
+func (starboardHandler) Backfill(s *discordgo.Session, guildID string, msg *discordgo.Message, board *database.Board) (bool, error) {
+	se := newStarboardEventAction(s, guildID, msg, board, actionBackfill)
+	err := se.await()
+	return se.posted, err
+}
+
+func (starboardHandler) Repair(s *discordgo.Session, guildID string, entry *database.Message) (framework.RepairResult, error) {
+	guild := database.GuildCache[guildID]
+	board := guild.FindBoard(entry.Board)
+	if board == nil {
+		return framework.RepairSkipped, nil
+	}
+
+	msg := &discordgo.Message{ID: entry.Original.MessageID, ChannelID: entry.Original.ChannelID, GuildID: guildID}
+	se := newStarboardEventAction(s, guildID, msg, board, actionRepair)
+	err := se.await()
+	return se.repaired, err
+}
+
+func (starboardHandler) Show(s *discordgo.Session, channelID string, entry *database.Message) (bool, error) {
+	return showEntry(s, channelID, entry)
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/framework"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+)
+
+//schedulerTick is how often the scheduler checks for due jobs.
+const schedulerTick = time.Minute
+
+var jobsOnce sync.Once
+
+//startJobs starts the scheduler once guilds are cached, so digests missed while Eugen was offline can be posted.
+func startJobs() {
+	jobsOnce.Do(func() {
+		for _, job := range []string{database.JobAutoRepair, database.JobCacheReset} {
+			s, err := database.FindSchedule("", job, "")
+			if err != nil {
+				log.Warnf("startJobs() -> database.FindSchedule(): %v", err)
+				continue
+			}
+
+			if s == nil {
+				err := database.SaveSchedule(database.NewGlobalSchedule(job))
+				if err != nil {
+					log.Warnf("startJobs() -> database.SaveSchedule(): %v", err)
+				}
+			}
+		}
+
+		go func() {
+			runSchedules()
+			for range time.Tick(schedulerTick) {
+				runSchedules()
+			}
+		}()
+	})
+}
+
+//runningJobs stores keys of schedules being run, so a slow job isn't started again by the next tick.
+var runningJobs sync.Map
+
+//runSchedules starts every due job in its own goroutine, so slow jobs like repairs don't hold up digests.
+func runSchedules() {
+	schedules, err := database.DueSchedules()
+	if err != nil {
+		log.Warnf("runSchedules() -> database.DueSchedules(): %v", err)
+		return
+	}
+
+	for _, s := range schedules {
+		key := strings.Join([]string{s.GuildID, s.Job, s.Period}, ":")
+		if _, running := runningJobs.LoadOrStore(key, true); running {
+			continue
+		}
+
+		go func(s *database.Schedule, key string) {
+			defer runningJobs.Delete(key)
+			runSchedule(s)
+		}(s, key)
+	}
+}
+
+//runSchedule runs a due job once for every missed run and schedules its next run. Runs missed while Eugen was offline are caught up right after a restart.
+func runSchedule(s *database.Schedule) {
+	var (
+		now   = time.Now()
+		loc   = time.UTC
+		guild = database.GuildCache[s.GuildID]
+	)
+
+	if guild != nil {
+		loc = guild.Location()
+	}
+
+	for _, scheduled := range s.Missed(now, loc) {
+		switch s.Job {
+		case database.JobAutoRepair:
+			autoRepair()
+		case database.JobCacheReset:
+			database.ResetMessageCache()
+		case database.JobDigest:
+			if guild != nil {
+				err := postDigest(dg, guild, s, scheduled)
+				if err != nil {
+					log.Warnf("runSchedule() -> postDigest(): %v. Guild ID: %v", err, s.GuildID)
+				}
+			}
+		case database.JobOnThisDay:
+			if guild != nil {
+				err := postOnThisDay(dg, guild, s, scheduled)
+				if err != nil {
+					log.Warnf("runSchedule() -> postOnThisDay(): %v. Guild ID: %v", err, s.GuildID)
+				}
+			}
+		}
+	}
+
+	s.LastRun = now
+	s.NextRun = s.Next(now, loc)
+	err := database.SaveSchedule(s)
+	if err != nil {
+		log.Warnf("runSchedule() -> database.SaveSchedule(): %v", err)
+	}
+}
+
+//autoRepair repairs starboard entries of guilds that enabled daily repairs.
+func autoRepair() {
+	guilds := make([]string, 0)
+	for _, guild := range database.GuildCache {
+		if guild.AutoRepair && guild.Enabled {
+			guilds = append(guilds, guild.ID)
+		}
+	}
+
+	for _, guildID := range guilds {
+		report, err := framework.RepairGuild(dg, guildID, nil)
+		if err != nil {
+			log.Warnf("autoRepair() -> RepairGuild(): %v. Guild ID: %v", err, guildID)
+			continue
+		}
+
+		log.Infof("Repaired guild %v. Checked: %v, updated: %v, original missing: %v, starboard missing: %v, failed: %v", guildID, report.Checked, report.Updated, report.OriginalMissing, report.StarboardMissing, report.Failed)
+	}
+}
+
+//postDigest posts a ranked list of the most starred entries of the period ending at the scheduled time.
+func postDigest(s *discordgo.Session, guild *database.Guild, schedule *database.Schedule, scheduled time.Time) error {
+	messages, err := database.TopMessages(&database.LeaderboardFilter{
+		GuildID: guild.ID,
+		Since:   schedule.Since(scheduled),
+	}, schedule.Top)
+	if err != nil {
+		return err
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+
+	var sb strings.Builder
+	for ind, msg := range messages {
+		author := "unknown author"
+		if msg.AuthorID != "" {
+			author = fmt.Sprintf("<@%v>", msg.AuthorID)
+		}
+
+		sb.WriteString(fmt.Sprintf("**%v.** %v %v by %v in <#%v> | [Jump](%v)\n", ind+1, guild.StarEmote, msg.Stars, author, msg.Original.ChannelID, msg.Original.Link(guild.ID)))
+	}
+
+	title := "🏆 Best of the week"
+	if schedule.Period == database.PeriodMonthly {
+		title = "🏆 Best of the month"
+	}
+
+	_, err = s.ChannelMessageSendEmbed(schedule.ChannelID, &discordgo.MessageEmbed{
+		Title:       title,
+		Description: sb.String(),
+		Color:       int(guild.EmbedColour),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("%v — %v", schedule.Since(scheduled).In(guild.Location()).Format("Jan 2"), scheduled.In(guild.Location()).Format("Jan 2, 2006")),
+		},
+		Timestamp: utils.EmbedTimestamp(),
+	})
+	return err
+}
+
+const (
+	//onThisDayLimit is the maximum number of entries reposted per day.
+	onThisDayLimit = 3
+	//onThisDaySample is how many entries are considered, some of them may be skipped.
+	onThisDaySample = 25
+)
+
+//postOnThisDay reposts entries starboarded on the scheduled date in earlier years. Snapshots are used for deleted originals, entries without either are skipped. So are entries from NSFW channels unless the channel is NSFW too.
+func postOnThisDay(s *discordgo.Session, guild *database.Guild, schedule *database.Schedule, scheduled time.Time) error {
+	var (
+		loc   = guild.Location()
+		today = scheduled.In(loc)
+	)
+
+	messages, err := database.OnThisDay(guild.ID, today, loc, onThisDaySample)
+	if err != nil {
+		return err
+	}
+
+	channelNSFW, err := utils.IsNSFWChannel(s, schedule.ChannelID)
+	if err != nil {
+		return err
+	}
+
+	var (
+		posted = 0
+		nsfw   = make(map[string]bool)
+	)
+
+	for _, msg := range messages {
+		if posted == onThisDayLimit {
+			break
+		}
+
+		if !channelNSFW {
+			isNSFW, ok := nsfw[msg.Original.ChannelID]
+			if !ok {
+				isNSFW, err = utils.IsNSFWChannel(s, msg.Original.ChannelID)
+				if err != nil {
+					continue
+				}
+				nsfw[msg.Original.ChannelID] = isNSFW
+			}
+
+			if isNSFW {
+				continue
+			}
+		}
+
+		var (
+			snapshot = msg.Snapshot
+			exists   = false
+		)
+
+		original, err := s.ChannelMessage(msg.Original.ChannelID, msg.Original.MessageID)
+		if err == nil {
+			snapshot = database.NewSnapshot(original)
+			exists = true
+		}
+
+		if snapshot == nil || guild.SpoilerMode() == database.SpoilerSkip && snapshot.HasSpoiler() {
+			continue
+		}
+
+		years := today.Year() - msg.CreatedAt.In(loc).Year()
+		title := "📅 One year ago today"
+		if years > 1 {
+			title = fmt.Sprintf("📅 %v years ago today", years)
+		}
+
+		embed := snapshotEmbed(guild, msg, snapshot, exists)
+		embed.Title = title
+
+		_, err = s.ChannelMessageSendEmbed(schedule.ChannelID, embed)
+		if err != nil {
+			return err
+		}
+		posted++
+	}
+
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"syscall"
 
 	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/framework"
 	"github.com/bwmarrin/discordgo"
 	log "github.com/sirupsen/logrus"
 )
@@ -25,12 +26,18 @@ func main() {
 		log.Fatalln("BOT_TOKEN env variable doesn't exit")
 	}
 
+	if err := database.Connect(); err != nil {
+		log.Fatalln("Error connecting to Mongo DB: ", err)
+	}
+
 	var err error
 	dg, err = discordgo.New("Bot " + token)
 	if err != nil {
 		log.Fatalln("Error creating a session: ", err)
 	}
 
+	framework.Starboard = starboardHandler{}
+
 	dg.AddHandler(onReady)
 	dg.AddHandler(messageCreated)
 	dg.AddHandler(guildCreated)
@@ -39,6 +46,7 @@ func main() {
 	dg.AddHandler(reactRemoved)
 	dg.AddHandler(allReactsRemoved)
 	dg.AddHandler(messageDeleted)
+	dg.AddHandler(messageUpdated)
 
 	if err := dg.Open(); err != nil {
 		log.Fatalln("Error opening connection,", err)
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+//Discordgo's MessageSend and MessageEdit only support a single embed, gallery posts are sent with these helpers instead.
+
+//messageBody builds a request body of a message with files, same as discordgo does for ChannelMessageSendComplex.
+func messageBody(data interface{}, files []*discordgo.File) (string, []byte, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(files) == 0 {
+		return "application/json", payload, nil
+	}
+
+	var (
+		body   = &bytes.Buffer{}
+		writer = multipart.NewWriter(body)
+	)
+
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", `form-data; name="payload_json"`)
+	h.Set("Content-Type", "application/json")
+
+	p, err := writer.CreatePart(h)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if _, err := p.Write(payload); err != nil {
+		return "", nil, err
+	}
+
+	for i, file := range files {
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file%d"; filename="%s"`, i, strings.ReplaceAll(file.Name, `"`, `\"`)))
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		h.Set("Content-Type", contentType)
+
+		p, err := writer.CreatePart(h)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if _, err := io.Copy(p, file.Reader); err != nil {
+			return "", nil, err
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return writer.FormDataContentType(), body.Bytes(), nil
+}
+
+//sendChannelMessage sends a message with several embeds and files to a channel.
+func sendChannelMessage(s *discordgo.Session, channelID string, embeds []*discordgo.MessageEmbed, files []*discordgo.File) (*discordgo.Message, error) {
+	contentType, body, err := messageBody(map[string]interface{}{
+		"embeds": embeds,
+	}, files)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := discordgo.EndpointChannelMessages(channelID)
+	response, err := s.RequestWithLockedBucket("POST", endpoint, contentType, body, s.Ratelimiter.LockBucket(endpoint), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &discordgo.Message{}
+	err = json.Unmarshal(response, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+//editChannelMessage replaces embeds of a message.
+func editChannelMessage(s *discordgo.Session, channelID, messageID string, embeds []*discordgo.MessageEmbed) error {
+	_, err := s.RequestWithBucketID("PATCH", discordgo.EndpointChannelMessage(channelID, messageID), map[string]interface{}{
+		"embeds": embeds,
+	}, discordgo.EndpointChannelMessage(channelID, ""))
+	return err
+}
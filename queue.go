@@ -1,6 +1,8 @@
 package main
 
 import (
+	"sync"
+
 	"github.com/VTGare/Eugen/database"
 	"github.com/sirupsen/logrus"
 )
@@ -9,26 +11,55 @@ var (
 	starboardQueue = newQueue()
 )
 
-type Queue map[database.MessagePair]chan *StarboardEvent
+//queueBuffer is how many events can wait for a message without blocking the sender.
+const queueBuffer = 16
+
+//Queue runs starboard events one at a time per message. It's safe for concurrent use.
+type Queue struct {
+	mu      sync.Mutex
+	workers map[database.MessagePair]*queueWorker
+}
+
+//queueWorker processes events of a single message. Pending counts pushed events that haven't finished yet, the worker stops once it reaches zero.
+type queueWorker struct {
+	events  chan *StarboardEvent
+	pending int
+}
+
+func newQueue() *Queue {
+	return &Queue{workers: make(map[database.MessagePair]*queueWorker)}
+}
 
-func newQueue() Queue {
-	return make(map[database.MessagePair]chan *StarboardEvent)
+func (q *Queue) Push(pair database.MessagePair, event *StarboardEvent) {
+	q.mu.Lock()
+	w, ok := q.workers[pair]
+	if !ok {
+		w = &queueWorker{events: make(chan *StarboardEvent, queueBuffer)}
+		q.workers[pair] = w
+		go q.work(pair, w)
+	}
+	w.pending++
+	q.mu.Unlock()
+
+	w.events <- event
 }
 
-func (q Queue) Push(pair database.MessagePair, event *StarboardEvent) {
-	if ch, ok := q[pair]; ok {
-		ch <- event
-	} else {
-		q[pair] = make(chan *StarboardEvent)
-		go func() {
-			for e := range q[pair] {
-				err := e.Run()
-				if err != nil {
-					logrus.Warnln("e.Run(): ", err)
-				}
-			}
-		}()
-
-		q[pair] <- event
+func (q *Queue) work(pair database.MessagePair, w *queueWorker) {
+	for e := range w.events {
+		err := e.Run()
+		if e.done != nil {
+			e.done <- err
+		} else if err != nil {
+			logrus.Warnln("e.Run(): ", err)
+		}
+
+		q.mu.Lock()
+		w.pending--
+		if w.pending == 0 {
+			delete(q.workers, pair)
+			q.mu.Unlock()
+			return
+		}
+		q.mu.Unlock()
 	}
 }
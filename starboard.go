@@ -1,14 +1,17 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/framework"
 	"github.com/VTGare/Eugen/services"
 	"github.com/VTGare/Eugen/utils"
 	"github.com/VTGare/embeds"
@@ -20,34 +23,102 @@ import (
 type StarboardEvent struct {
 	React       *discordgo.MessageReactions
 	guild       *database.Guild
+	target      *database.Board
 	session     *discordgo.Session
 	message     *discordgo.Message
 	board       *database.Message
 	addEvent    *discordgo.MessageReactionAdd
 	removeEvent *discordgo.MessageReactionRemove
 	deleteEvent *discordgo.MessageDelete
+	updateEvent *discordgo.MessageUpdate
+	votes       []*database.Vote
 	selfstar    bool
+	raw         int
+	weighted    bool
+	synced      bool
+	userID      string
+	action      modAction
+	done        chan error
+	posted      bool
+	repaired    framework.RepairResult
+	route       *database.RouteContext
+	//skipFiles makes createEmbed only check if files fit instead of downloading them. Files of an edited post are already attached.
+	skipFiles bool
 }
 
+//modAction is a starboard action requested by a command.
+type modAction int
+
+const (
+	actionNone modAction = iota
+	actionStar
+	actionUnstar
+	actionBackfill
+	actionRepair
+	actionApprove
+	actionPromote
+	actionDemote
+	actionFreeze
+	actionUnfreeze
+)
+
 type StarboardFile struct {
 	Name      string
 	URL       string
+	Size      int64
 	Thumbnail *os.File
 	Resp      *http.Response
 }
 
-func newStarboardEventAdd(s *discordgo.Session, r *discordgo.MessageReactionAdd, msg *discordgo.Message, emote *discordgo.MessageReactions) (*StarboardEvent, error) {
+//galleryLimit is the number of images Discord groups into one gallery.
+const galleryLimit = 4
+
+//awaitTimeout is how long commands wait for their starboard events.
+const awaitTimeout = 30 * time.Second
+
+//reactionPages is the maximum number of pages of reacting users requested when votes are synced.
+const reactionPages = 50
+
+//StarboardMessage is a rendered starboard post. Discord groups embeds sharing the same URL into a gallery, so every embed after the first one only carries an extra image.
+type StarboardMessage struct {
+	Embeds    []*discordgo.MessageEmbed
+	Files     []*discordgo.File
+	responses []*http.Response
+}
+
+//Embed returns the main embed of a starboard post.
+func (m *StarboardMessage) Embed() *discordgo.MessageEmbed {
+	return m.Embeds[0]
+}
+
+//addFile attaches a downloaded file to the post.
+func (m *StarboardMessage) addFile(file *StarboardFile) {
+	m.responses = append(m.responses, file.Resp)
+	m.Files = append(m.Files, &discordgo.File{
+		Name:   file.Name,
+		Reader: file.Resp.Body,
+	})
+}
+
+//Close closes bodies of all downloaded files.
+func (m *StarboardMessage) Close() {
+	for _, resp := range m.responses {
+		resp.Body.Close()
+	}
+}
+
+func newStarboardEventAdd(s *discordgo.Session, r *discordgo.MessageReactionAdd, msg *discordgo.Message, board *database.Board, emote *discordgo.MessageReactions) (*StarboardEvent, error) {
 	guild := database.GuildCache[r.GuildID]
-	se := &StarboardEvent{guild: guild, message: msg, session: s, addEvent: r, removeEvent: nil, React: emote}
+	se := &StarboardEvent{guild: guild, target: board, message: msg, session: s, addEvent: r, removeEvent: nil, React: emote}
 
 	return se, nil
 }
 
-func newStarboardEventRemove(s *discordgo.Session, r *discordgo.MessageReactionRemove, msg *discordgo.Message) (*StarboardEvent, error) {
+func newStarboardEventRemove(s *discordgo.Session, r *discordgo.MessageReactionRemove, msg *discordgo.Message, board *database.Board) (*StarboardEvent, error) {
 	guild := database.GuildCache[r.GuildID]
 
-	emote := FindReact(msg, guild.StarEmote)
-	se := &StarboardEvent{guild: guild, message: msg, session: s, addEvent: nil, removeEvent: r, React: emote}
+	emote := utils.FindReact(msg, board.StarEmote)
+	se := &StarboardEvent{guild: guild, target: board, message: msg, session: s, addEvent: nil, removeEvent: r, React: emote}
 
 	return se, nil
 }
@@ -58,22 +129,98 @@ func newStarboardEventDeleted(s *discordgo.Session, d *discordgo.MessageDelete)
 	return &StarboardEvent{guild: guild, message: &discordgo.Message{ID: d.ID, ChannelID: d.ChannelID}, session: s, addEvent: nil, removeEvent: nil, deleteEvent: d}, nil
 }
 
+func newStarboardEventUpdated(s *discordgo.Session, u *discordgo.MessageUpdate, msg *discordgo.Message, board *database.Board) (*StarboardEvent, error) {
+	guild := database.GuildCache[u.GuildID]
+
+	emote := utils.FindReact(msg, board.StarEmote)
+	return &StarboardEvent{guild: guild, target: board, message: msg, session: s, updateEvent: u, React: emote}, nil
+}
+
+func newStarboardEventAction(s *discordgo.Session, guildID string, msg *discordgo.Message, board *database.Board, action modAction) *StarboardEvent {
+	guild := database.GuildCache[guildID]
+
+	emote := utils.FindReact(msg, board.StarEmote)
+	return &StarboardEvent{guild: guild, target: board, message: msg, session: s, React: emote, action: action}
+}
+
+//await pushes the event to the starboard queue and waits until it's processed or awaitTimeout passes. The event still runs after a timeout.
+func (se *StarboardEvent) await() error {
+	se.done = make(chan error, 1)
+	starboardQueue.Push(database.NewPair(se.message.ChannelID, se.message.ID), se)
+
+	select {
+	case err := <-se.done:
+		return err
+	case <-time.After(awaitTimeout):
+		return errors.New("timed out waiting for the starboard, the action will be finished in the background")
+	}
+}
+
 func (se *StarboardEvent) Run() error {
-	var err error
+	if se.deleteEvent != nil {
+		return se.deleteStarboard()
+	}
 
-	se.board, err = database.Repost(se.message.ChannelID, se.message.ID)
+	var err error
+	se.board, err = database.Repost(se.message.ChannelID, se.message.ID, se.target.Name)
 	if err != nil {
 		return err
 	}
 
-	if se.deleteEvent != nil {
-		se.deleteStarboard()
-	} else if se.isStarboarded() {
-		self, err := se.isSelfStar()
+	switch se.action {
+	case actionStar:
+		return se.forceStarboard()
+	case actionUnstar:
+		return se.unstarStarboard()
+	case actionRepair:
+		return se.repairStarboard()
+	case actionApprove:
+		return se.approveStarboard()
+	case actionPromote:
+		if !se.isStarboarded() {
+			return fmt.Errorf("message is not on %v board", se.target.DisplayName())
+		}
+
+		react := se.React
+		if react == nil {
+			react = &discordgo.MessageReactions{Count: se.board.Stars, Emoji: boardEmoji(se.target)}
+		}
+		return se.promoteStarboard(react, true)
+	case actionDemote:
+		return se.demoteStarboard()
+	case actionFreeze:
+		return se.freezeStarboard(true)
+	case actionUnfreeze:
+		return se.freezeStarboard(false)
+	case actionBackfill:
+		if se.isStarboarded() {
+			return nil
+		}
+
+		err := se.countVotes()
+		if err != nil {
+			return err
+		}
+		return se.createStarboard()
+	}
+
+	if se.updateEvent != nil {
+		if se.isStarboarded() {
+			return se.updateStarboard()
+		}
+		return nil
+	}
+
+	if se.isStarboarded() && se.board.Frozen {
+		logrus.Infof("Ignoring reaction on a frozen starboard %v in channel %v", se.board.Starboard.MessageID, se.board.Starboard.ChannelID)
+		return nil
+	}
+
+	if se.isStarboarded() {
+		err := se.countVotes()
 		if err != nil {
 			return err
 		}
-		se.selfstar = self
 
 		switch {
 		case se.addEvent != nil:
@@ -82,11 +229,10 @@ func (se *StarboardEvent) Run() error {
 			se.decrementStarboard()
 		}
 	} else if se.addEvent != nil {
-		self, err := se.isSelfStar()
+		err := se.countVotes()
 		if err != nil {
 			return err
 		}
-		se.selfstar = self
 
 		se.createStarboard()
 	}
@@ -94,100 +240,538 @@ func (se *StarboardEvent) Run() error {
 	return nil
 }
 
+//routeContext resolves the original channel for routing rules once per event.
+func (se *StarboardEvent) routeContext(ch *discordgo.Channel) (*database.RouteContext, error) {
+	if se.route == nil {
+		route, err := utils.NewRouteContext(se.session, ch)
+		if err != nil {
+			return nil, err
+		}
+		se.route = route
+	}
+
+	return se.route, nil
+}
+
 func (se *StarboardEvent) isStarboarded() bool {
 	return se.board != nil
 }
 
-func (se *StarboardEvent) isSelfStar() (bool, error) {
+//countVotes sets star count and selfstar flag from votes collection. If Discord's reaction count disagrees with stored votes, votes are synced with reacting users first. Star count is a weighted score of eligible votes without a forbidden selfstar, raw count of those votes is kept for the footer.
+func (se *StarboardEvent) countVotes() error {
 	if se.React == nil {
+		return nil
+	}
+
+	votes, err := database.Votes(se.message.ChannelID, se.message.ID, se.target.StarEmote)
+	if err != nil {
+		return fmt.Errorf("database.Votes(): %v", err)
+	}
+
+	se.synced = false
+	if len(votes) != se.React.Count {
+		se.synced = true
+		users, complete, err := se.reactingUsers()
+		if err != nil {
+			return fmt.Errorf("se.reactingUsers(): %v", err)
+		}
+
+		votes, err = database.SyncVotes(se.guild.ID, se.message.ChannelID, se.message.ID, se.target.StarEmote, users, complete)
+		if err != nil {
+			return fmt.Errorf("database.SyncVotes(): %v", err)
+		}
+	}
+
+	var (
+		score = 0
+		raw   = 0
+	)
+
+	se.votes = votes
+	se.selfstar = false
+	se.weighted = se.guild.IsWeighted()
+	for _, vote := range votes {
+		if !se.isEligible(vote) {
+			continue
+		}
+
+		weight := 1
+		if se.weighted {
+			weight, err = se.voteWeight(vote.UserID)
+			if err != nil {
+				return fmt.Errorf("se.voteWeight(): %v", err)
+			}
+
+			if weight == 0 {
+				continue
+			}
+		}
+
+		if se.message.Author != nil && vote.UserID == se.message.Author.ID {
+			se.selfstar = true
+			if !se.target.Selfstar {
+				continue
+			}
+		}
+
+		score += weight
+		raw++
+	}
+
+	se.React.Count = score
+	se.raw = raw
+
+	return nil
+}
+
+//isEligible checks if a vote can count. Reactions of Eugen and other bots, banned voters and accounts younger than guild's minimum account age don't.
+func (se *StarboardEvent) isEligible(vote *database.Vote) bool {
+	if vote.Bot || vote.UserID == se.session.State.User.ID || se.guild.IsBannedVoter(vote.UserID) {
+		return false
+	}
+
+	if days := se.guild.MinAccountAge; days > 0 {
+		created, err := discordgo.SnowflakeTimestamp(vote.UserID)
+		if err != nil || time.Since(created) < time.Duration(days)*24*time.Hour {
+			return false
+		}
+	}
+
+	return true
+}
+
+//reactingUsers returns users who reacted with board's emote. Discord returns at most 100 users per request, so at most reactionPages pages are requested. Returns false if there were more users than that.
+func (se *StarboardEvent) reactingUsers() ([]*discordgo.User, bool, error) {
+	var (
+		users = make([]*discordgo.User, 0, se.React.Count)
+		after = ""
+	)
+
+	for i := 0; i < reactionPages; i++ {
+		page, err := se.session.MessageReactions(se.message.ChannelID, se.message.ID, se.React.Emoji.APIName(), 100, "", after)
+		if err != nil {
+			return nil, false, err
+		}
+
+		users = append(users, page...)
+		if len(page) < 100 {
+			return users, true, nil
+		}
+		after = page[len(page)-1].ID
+	}
+
+	return users, false, nil
+}
+
+//voteWeight returns how many stars a vote of a member is worth. Votes of members who left the server only count if voting isn't restricted to roles.
+func (se *StarboardEvent) voteWeight(userID string) (int, error) {
+	member, err := se.member(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if member == nil {
+		return se.guild.VoteWeight(nil), nil
+	}
+
+	return se.guild.VoteWeight(member.Roles), nil
+}
+
+//member returns a guild member from state or API, nil if the user has left the guild.
+func (se *StarboardEvent) member(userID string) (*discordgo.Member, error) {
+	member, err := se.session.State.Member(se.guild.ID, userID)
+	if err != nil {
+		member, err = se.session.GuildMember(se.guild.ID, userID)
+		if err != nil {
+			if utils.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+
+		member.GuildID = se.guild.ID
+		se.session.State.MemberAdd(member)
+	}
+
+	return member, nil
+}
+
+//isNewVoter checks if user's account was created or they joined the guild less than given number of days ago.
+func (se *StarboardEvent) isNewVoter(userID string, days int) (bool, error) {
+	since := time.Duration(days) * 24 * time.Hour
+	created, err := discordgo.SnowflakeTimestamp(userID)
+	if err == nil && time.Since(created) < since {
+		return true, nil
+	}
+
+	member, err := se.member(userID)
+	if err != nil || member == nil {
+		return false, err
+	}
+
+	joined, err := member.JoinedAt.Parse()
+	if err != nil {
 		return false, nil
 	}
 
-	users, err := se.session.MessageReactions(se.message.ChannelID, se.message.ID, se.React.Emoji.APIName(), 100, "", "")
+	return time.Since(joined) < since, nil
+}
+
+//detectBrigade checks if message's stars look like a raid: too many of them arrived within guild's time window or most of them came from new accounts and members. Returns a flag naming suspicious voters or nil.
+func (se *StarboardEvent) detectBrigade() (*database.Flag, error) {
+	settings := se.guild.Brigade
+	var (
+		since      = time.Now().Add(-time.Duration(settings.Window) * time.Minute)
+		reasons    = make([]string, 0, 2)
+		recent     = make([]string, 0)
+		newVoters  = make([]string, 0)
+		counted    = 0
+		suspicious = make(map[string]bool)
+	)
+
+	for _, vote := range se.votes {
+		if !se.isEligible(vote) {
+			continue
+		}
+		counted++
+
+		//Synced votes don't have real timestamps.
+		if !se.synced && vote.CreatedAt.After(since) {
+			recent = append(recent, vote.UserID)
+		}
+
+		if settings.NewDays > 0 && settings.Ratio > 0 {
+			isNew, err := se.isNewVoter(vote.UserID, settings.NewDays)
+			if err != nil {
+				return nil, fmt.Errorf("se.isNewVoter(): %v", err)
+			}
+
+			if isNew {
+				newVoters = append(newVoters, vote.UserID)
+			}
+		}
+	}
+
+	if settings.Stars > 0 && len(recent) >= settings.Stars {
+		reasons = append(reasons, fmt.Sprintf("%v stars in %v minutes", len(recent), settings.Window))
+		for _, id := range recent {
+			suspicious[id] = true
+		}
+	}
+
+	if counted != 0 && len(newVoters) != 0 && float64(len(newVoters))/float64(counted) >= settings.Ratio {
+		reasons = append(reasons, fmt.Sprintf("%v of %v voters are new accounts or members", len(newVoters), counted))
+		for _, id := range newVoters {
+			suspicious[id] = true
+		}
+	}
+
+	if len(reasons) == 0 {
+		return nil, nil
+	}
+
+	pair := database.NewPair(se.message.ChannelID, se.message.ID)
+	flag := database.NewFlag(&pair, se.guild.ID, se.target.Name)
+	flag.Reason = strings.Join(reasons, ", ")
+	flag.Stars = se.React.Count
+	if se.message.Author != nil {
+		flag.AuthorID = se.message.Author.ID
+	}
+
+	for _, vote := range se.votes {
+		if suspicious[vote.UserID] {
+			flag.Voters = append(flag.Voters, vote.UserID)
+		}
+	}
+
+	return flag, nil
+}
+
+//holdBrigaded keeps messages with suspicious stars off the board until moderators review them. Returns true if the message is held.
+func (se *StarboardEvent) holdBrigaded() (bool, error) {
+	if se.guild.Brigade == nil {
+		return false, nil
+	}
+
+	pair := database.NewPair(se.message.ChannelID, se.message.ID)
+	flag, err := database.FindFlag(&pair, se.target.Name)
+	if err != nil {
+		return false, fmt.Errorf("database.FindFlag(): %v", err)
+	}
+
+	if flag != nil {
+		return !flag.Cleared, nil
+	}
+
+	flag, err = se.detectBrigade()
+	if err != nil || flag == nil {
+		return false, err
+	}
+
+	err = database.InsertFlag(flag)
 	if err != nil {
-		return false, fmt.Errorf("MessageReactions(): %v", err)
+		return false, fmt.Errorf("database.InsertFlag(): %v", err)
+	}
+
+	logrus.Infof("Holding message %v in channel %v for review: %v", se.message.ID, se.message.ChannelID, flag.Reason)
+	se.logBrigade(flag)
+	return true, nil
+}
+
+//logBrigade reports a held message to guild's brigade log channel.
+func (se *StarboardEvent) logBrigade(flag *database.Flag) {
+	channelID := se.guild.Brigade.LogChannel
+	if channelID == "" {
+		return
 	}
 
-	for _, user := range users {
-		if user.ID == se.message.Author.ID {
-			return true, nil
+	voters := make([]string, 0, len(flag.Voters))
+	for ind, id := range flag.Voters {
+		if ind == 50 {
+			voters = append(voters, fmt.Sprintf("and %v more", len(flag.Voters)-ind))
+			break
 		}
+		voters = append(voters, fmt.Sprintf("<@%v>", id))
 	}
 
-	return false, nil
+	if len(voters) == 0 {
+		voters = append(voters, "none")
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "⚠️ Possible star raid",
+		URL:         flag.Original.Link(se.guild.ID),
+		Description: fmt.Sprintf("A message in <#%v> was held off %v board for review.\nUse ``star`` to post it or ``unflag`` to let it through.", se.message.ChannelID, se.target.DisplayName()),
+		Color:       utils.EmbedColor,
+		Timestamp:   utils.EmbedTimestamp(),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Reason", Value: flag.Reason},
+			{Name: "Stars", Value: strconv.Itoa(flag.Stars), Inline: true},
+			{Name: "Suspicious voters", Value: strings.Join(voters, " ")},
+		},
+	}
+
+	_, err := se.session.ChannelMessageSendEmbed(channelID, embed)
+	if err != nil {
+		logrus.Warnf("Failed to log held message to %v: %v", channelID, err)
+	}
 }
 
 func (se *StarboardEvent) createStarboard() error {
-	required := se.guild.StarsRequired(se.addEvent.ChannelID)
+	required := se.guild.BoardStarsRequired(se.target, se.message.ChannelID)
 	if react := se.React; react != nil {
-		if se.selfstar && !se.guild.Selfstar {
-			react.Count--
-		}
-
 		if react.Count >= required {
 			ch, err := se.session.Channel(se.message.ChannelID)
 			if err != nil {
 				return err
 			}
 
-			embed, resp, err := se.createEmbed(react, ch)
+			route, err := se.routeContext(ch)
 			if err != nil {
 				return err
 			}
 
-			if embed != nil {
-				logrus.Infof("Creating a new starboard. Guild: %v, channel: %v, message: %v", se.guild.Name, se.addEvent.ChannelID, se.addEvent.MessageID)
+			if route.NSFW && !se.target.NSFW {
+				return nil
+			}
 
-				starboardChannel := ""
-				if ch.NSFW && se.guild.NSFWStarboardChannel != "" {
-					starboardChannel = se.guild.NSFWStarboardChannel
-				} else {
-					starboardChannel = se.guild.StarboardChannel
-				}
+			if se.guild.SpoilerMode() == database.SpoilerSkip && utils.HasSpoiler(se.message) {
+				logrus.Infof("Skipping spoilered message %v in channel %v", se.message.ID, se.message.ChannelID)
+				return nil
+			}
 
-				starboard, err := se.session.ChannelMessageSendComplex(starboardChannel, embed)
-				if err != nil {
-					return err
-				}
+			held, err := se.holdBrigaded()
+			if err != nil || held {
+				return err
+			}
 
-				if resp != nil {
-					resp.Body.Close()
-				}
+			queued, err := se.queueApproval(react, ch)
+			if err != nil || queued {
+				return err
+			}
+
+			return se.postStarboard(react, ch, false)
+		}
+	}
+
+	return nil
+}
+
+//postStarboard sends a starboard message and stores a new entry for it.
+func (se *StarboardEvent) postStarboard(react *discordgo.MessageReactions, ch *discordgo.Channel, forced bool) error {
+	route, err := se.routeContext(ch)
+	if err != nil {
+		return err
+	}
+
+	embed, err := se.createEmbed(react, ch)
+	if err != nil {
+		return err
+	}
 
-				handleError(se.session, se.addEvent.ChannelID, err)
-				oPair := database.NewPair(se.message.ChannelID, se.message.ID)
-				sPair := database.NewPair(starboard.ChannelID, starboard.ID)
-				err = database.InsertOneMessage(database.NewMessage(&oPair, &sPair, se.addEvent.GuildID))
-				handleError(se.session, se.addEvent.ChannelID, err)
+	if embed != nil {
+		defer embed.Close()
+		destination, _ := se.guild.Route(se.target, route)
+
+		logrus.Infof("Creating a new starboard. Guild: %v, board: %v, channel: %v, message: %v, forced: %v", se.guild.Name, se.target.DisplayName(), se.message.ChannelID, se.message.ID, forced)
+
+		var (
+			starboard *discordgo.Message
+			webhook   *database.Webhook
+		)
+
+		if se.target.Webhook {
+			params := &discordgo.WebhookParams{
+				Username:  webhookUsername(se.message),
+				AvatarURL: se.message.Author.AvatarURL(""),
+				Embeds:    embed.Embeds,
 			}
+			starboard, webhook, err = sendWebhookMessage(se.session, se.guild, destination, params, embed.Files)
+		} else {
+			starboard, err = sendChannelMessage(se.session, destination, embed.Embeds, embed.Files)
+		}
+		if err != nil {
+			return err
+		}
+
+		oPair := database.NewPair(se.message.ChannelID, se.message.ID)
+		sPair := database.NewPair(starboard.ChannelID, starboard.ID)
+		entry := database.NewMessage(&oPair, &sPair, se.guild.ID, se.target.Name)
+		entry.AuthorID = se.message.Author.ID
+		entry.Stars = react.Count
+		entry.Forced = forced
+		entry.Snapshot = database.NewSnapshot(se.message)
+		if webhook != nil {
+			entry.WebhookID = webhook.ID
+		}
+		err = database.InsertOneMessage(entry)
+		handleError(se.session, se.message.ChannelID, err)
+		se.posted = true
+
+		action := database.AuditCreate
+		if se.action == actionStar {
+			action = database.AuditStar
 		}
+		se.audit(action, entry, 0, react.Count, "")
 	}
 
 	return nil
 }
 
-func (se *StarboardEvent) incrementStarboard() {
-	if react := se.React; react != nil {
-		if se.selfstar && !se.guild.Selfstar {
-			react.Count--
+//forceStarboard posts a message to the board regardless of its star count, or marks an existing entry as forced.
+func (se *StarboardEvent) forceStarboard() error {
+	if se.isStarboarded() {
+		if se.board.Forced {
+			return fmt.Errorf("message is already on %v board", se.target.DisplayName())
 		}
+		err := database.SetMessageForced(se.board.Original, se.board.Board, true)
+		if err != nil {
+			return err
+		}
+
+		se.audit(database.AuditStar, se.board, se.board.Stars, se.board.Stars, "Existing entry was marked as forced")
+		return nil
+	}
+
+	err := se.countVotes()
+	if err != nil {
+		return err
+	}
+
+	react := se.React
+	if react == nil {
+		react = &discordgo.MessageReactions{Count: 0, Emoji: boardEmoji(se.target)}
+	}
+
+	ch, err := se.session.Channel(se.message.ChannelID)
+	if err != nil {
+		return err
+	}
+
+	err = se.postStarboard(react, ch, true)
+	if err != nil {
+		return err
+	}
+
+	pair := database.NewPair(se.message.ChannelID, se.message.ID)
+	return database.DeleteFlag(&pair, se.target.Name)
+}
+
+//unstarStarboard takes a message down from the board.
+func (se *StarboardEvent) unstarStarboard() error {
+	if !se.isStarboarded() {
+		return fmt.Errorf("message is not on %v board", se.target.DisplayName())
+	}
+
+	starboard, err := se.session.ChannelMessage(se.board.Starboard.ChannelID, se.board.Starboard.MessageID)
+	if err != nil {
+		logrus.Warnln("se.session.ChannelMessage():", err)
+		se.audit(database.AuditUnstar, se.board, se.board.Stars, 0, "Starboard message was already gone")
+		return database.DeleteMessage(se.board.Original, se.board.Board)
+	}
+
+	se.removeStarboard(starboard)
+	return nil
+}
+
+//freezeStarboard freezes or unfreezes star count of an entry. Frozen entries are neither edited nor removed by reactions.
+func (se *StarboardEvent) freezeStarboard(frozen bool) error {
+	if !se.isStarboarded() {
+		return fmt.Errorf("message is not on %v board", se.target.DisplayName())
+	}
+
+	err := database.SetMessageFrozen(se.board.Original, se.board.Board, frozen)
+	if err != nil {
+		return err
+	}
+
+	action := database.AuditUnfreeze
+	if frozen {
+		action = database.AuditFreeze
+	}
+
+	se.audit(action, se.board, se.board.Stars, se.board.Stars, "")
+	return nil
+}
 
+func (se *StarboardEvent) incrementStarboard() {
+	if react := se.React; react != nil {
 		msg, err := se.session.ChannelMessage(se.board.Starboard.ChannelID, se.board.Starboard.MessageID)
 		if err != nil {
-			if strings.Contains(err.Error(), "404 Not Found") {
+			if utils.IsNotFound(err) {
 				logrus.Infoln("Unknown starboard cached. Removing.")
-				err := database.DeleteMessage(&database.MessagePair{ChannelID: se.message.ChannelID, MessageID: se.message.ID})
+				se.audit(database.AuditDelete, se.board, se.board.Stars, 0, "Starboard message is gone, posting it again")
+				err := database.DeleteMessage(se.board.Original, se.board.Board)
 				if err != nil {
 					logrus.Warnln("database.DeleteMessage(): ", err)
+					return
+				}
+
+				//Starboard message is gone, create it again instead of leaving the original orphaned.
+				se.board = nil
+				err = se.createStarboard()
+				if err != nil {
+					logrus.Warnln("se.createStarboard(): ", err)
 				}
 				return
 			}
 			logrus.Warnln("se.session.ChannelMessage(): ", err)
+		} else if !se.isOwnMessage(msg) {
+			se.replaceStarboard(msg, react)
 		} else {
-			embed := se.editStarboard(msg, react)
-			if embed != nil {
+			embeds := se.editStarboard(msg, react)
+			if embeds != nil {
 				logrus.Infoln(fmt.Sprintf("Editing starboard (adding) %v in channel %v", msg.ID, msg.ChannelID))
-				se.session.ChannelMessageEditEmbed(msg.ChannelID, msg.ID, embed)
+				err := editStarboardMessage(se.session, se.guild, se.board, embeds)
+				if err != nil {
+					logrus.Warnln("editStarboardMessage():", err)
+				}
+				se.updateStars(react.Count)
+			}
+
+			err := se.promoteStarboard(react, false)
+			if err != nil {
+				logrus.Warnln("se.promoteStarboard():", err)
 			}
 		}
 	}
@@ -196,9 +780,10 @@ func (se *StarboardEvent) incrementStarboard() {
 func (se *StarboardEvent) decrementStarboard() {
 	starboard, err := se.session.ChannelMessage(se.board.Starboard.ChannelID, se.board.Starboard.MessageID)
 	if err != nil {
-		if strings.Contains(err.Error(), "404 Not Found") {
+		if utils.IsNotFound(err) {
 			logrus.Infoln("Unknown starboard cached. Removing.")
-			err := database.DeleteMessage(&database.MessagePair{ChannelID: se.message.ChannelID, MessageID: se.message.ID})
+			se.audit(database.AuditDelete, se.board, se.board.Stars, 0, "Starboard message is gone")
+			err := database.DeleteMessage(se.board.Original, se.board.Board)
 			if err != nil {
 				logrus.Warnln("database.DeleteMessage(): ", err)
 			}
@@ -212,33 +797,272 @@ func (se *StarboardEvent) decrementStarboard() {
 		return
 	}
 
-	required := se.guild.StarsRequired(se.removeEvent.ChannelID)
-	if react := se.React; react != nil {
-		if se.selfstar && !se.guild.Selfstar {
-			react.Count--
-		}
+	react := se.React
+	if react == nil {
+		react = &discordgo.MessageReactions{Count: 0, Emoji: &discordgo.Emoji{}}
+	}
+
+	var (
+		required = se.guild.BoardStarsRequired(se.target, se.message.ChannelID)
+		removal  = se.guild.RemovalThreshold(se.message.ChannelID)
+	)
 
-		if react.Count <= required/2 {
-			err := se.session.ChannelMessageDelete(starboard.ChannelID, starboard.ID)
+	if !se.board.Forced && removal.ShouldRemove(react.Count, required) {
+		se.removeStarboard(starboard)
+	} else if !se.isOwnMessage(starboard) {
+		se.replaceStarboard(starboard, react)
+	} else {
+		embeds := se.editStarboard(starboard, react)
+		if embeds != nil {
+			logrus.Infof("Editing starboard (subtracting) %v in channel %v", se.board.Starboard.MessageID, se.board.Starboard.ChannelID)
+			err := editStarboardMessage(se.session, se.guild, se.board, embeds)
 			if err != nil {
-				logrus.Warnln("se.session.ChannelMessageDelete():", err)
-			}
-		} else {
-			embed := se.editStarboard(starboard, react)
-			if embed != nil {
-				logrus.Infof("Editing starboard (subtracting) %v in channel %v", se.board.Starboard.MessageID, se.board.Starboard.ChannelID)
-				_, err := se.session.ChannelMessageEditEmbed(starboard.ChannelID, starboard.ID, embed)
-				if err != nil {
-					logrus.Warnln("se.session.ChannelMessageEditEmbed():", err)
-				}
+				logrus.Warnln("editStarboardMessage():", err)
 			}
+			se.updateStars(react.Count)
 		}
-	} else {
-		err := se.session.ChannelMessageDelete(starboard.ChannelID, starboard.ID)
+	}
+}
+
+//isOwnMessage checks if a starboard message was posted by Eugen or its webhook. Entries imported from other bots point to their messages.
+func (se *StarboardEvent) isOwnMessage(msg *discordgo.Message) bool {
+	return msg.Author == nil || msg.Author.ID == se.session.State.User.ID || se.guild.WebhookByID(msg.WebhookID) != nil
+}
+
+//replaceStarboard replaces a starboard message imported from another bot with Eugen's own, since other bots' messages can't be edited.
+func (se *StarboardEvent) replaceStarboard(starboard *discordgo.Message, react *discordgo.MessageReactions) {
+	ch, err := se.session.Channel(se.message.ChannelID)
+	if err != nil {
+		logrus.Warnln("se.session.Channel():", err)
+		return
+	}
+
+	logrus.Infof("Replacing imported starboard %v in channel %v", starboard.ID, starboard.ChannelID)
+	forced := se.board.Forced
+	se.removeStarboard(starboard)
+	se.board = nil
+
+	err = se.postStarboard(react, ch, forced)
+	if err != nil {
+		logrus.Warnln("se.postStarboard():", err)
+	}
+}
+
+//removeStarboard deletes a starboard message and its database entry, so the original can be starboarded again later.
+func (se *StarboardEvent) removeStarboard(starboard *discordgo.Message) {
+	logrus.Infof("Removing starboard %v in channel %v", starboard.ID, starboard.ChannelID)
+
+	var (
+		action = database.AuditRemove
+		after  = 0
+	)
+
+	if se.action == actionUnstar {
+		action = database.AuditUnstar
+	}
+
+	if se.React != nil {
+		after = se.React.Count
+	}
+	se.audit(action, se.board, se.board.Stars, after, "")
+	se.removeFame(se.board)
+
+	err := deleteStarboardMessage(se.session, se.guild, se.board)
+	if err != nil {
+		logrus.Warnln("deleteStarboardMessage():", err)
+	}
+
+	err = database.DeleteMessage(se.board.Original, se.board.Board)
+	if err != nil {
+		logrus.Warnln("database.DeleteMessage():", err)
+	}
+}
+
+//repairStarboard removes an entry if either its original or starboard message is gone, otherwise it recounts stars and fixes the footer.
+func (se *StarboardEvent) repairStarboard() error {
+	se.repaired = framework.RepairOK
+	if !se.isStarboarded() {
+		return nil
+	}
+
+	if se.board.Frozen {
+		se.repaired = framework.RepairSkipped
+		return nil
+	}
+
+	original, err := se.session.ChannelMessage(se.message.ChannelID, se.message.ID)
+	if err != nil {
+		if !utils.IsNotFound(err) {
+			return err
+		}
+
+		logrus.Infof("Repair: original %v in channel %v is gone. Removing starboard.", se.message.ID, se.message.ChannelID)
+		err := deleteStarboardMessage(se.session, se.guild, se.board)
+		if err != nil && !utils.IsNotFound(err) {
+			return err
+		}
+
+		se.repaired = framework.RepairOriginalMissing
+		se.audit(database.AuditDelete, se.board, se.board.Stars, 0, "Repair: original message is gone")
+		se.removeFame(se.board)
+		return database.DeleteMessage(se.board.Original, se.board.Board)
+	}
+
+	starboard, err := se.session.ChannelMessage(se.board.Starboard.ChannelID, se.board.Starboard.MessageID)
+	if err != nil {
+		if !utils.IsNotFound(err) {
+			return err
+		}
+
+		logrus.Infof("Repair: starboard %v in channel %v is gone. Removing entry.", se.board.Starboard.MessageID, se.board.Starboard.ChannelID)
+		se.repaired = framework.RepairStarboardMissing
+		se.audit(database.AuditDelete, se.board, se.board.Stars, 0, "Repair: starboard message is gone")
+		se.removeFame(se.board)
+		return database.DeleteMessage(se.board.Original, se.board.Board)
+	}
+
+	if len(starboard.Embeds) == 0 || starboard.Embeds[0].Footer == nil {
+		return nil
+	}
+
+	original.GuildID = se.guild.ID
+	se.message = original
+	se.React = utils.FindReact(original, se.target.StarEmote)
+	err = se.countVotes()
+	if err != nil {
+		return err
+	}
+
+	react := se.React
+	if react == nil {
+		react = &discordgo.MessageReactions{Count: 0, Emoji: boardEmoji(se.target)}
+	}
+
+	if se.board.AuthorID == "" && original.Author != nil {
+		err := database.SetMessageAuthor(se.board.Original, se.board.Board, original.Author.ID)
 		if err != nil {
-			logrus.Warnln("se.session.ChannelMessageDelete(): ", err)
+			logrus.Warnln("database.SetMessageAuthor():", err)
 		}
 	}
+
+	if !se.isOwnMessage(starboard) {
+		if se.board.Stars != react.Count {
+			se.updateStars(react.Count)
+			se.repaired = framework.RepairUpdated
+		}
+		return nil
+	}
+
+	if embeds := se.editStarboard(starboard, react); embeds != nil {
+		logrus.Infof("Repair: fixing starboard %v in channel %v", starboard.ID, starboard.ChannelID)
+		err := editStarboardMessage(se.session, se.guild, se.board, embeds)
+		if err != nil {
+			return err
+		}
+		se.repaired = framework.RepairUpdated
+	}
+
+	if se.board.Stars != react.Count {
+		se.updateStars(react.Count)
+		se.repaired = framework.RepairUpdated
+	}
+
+	return nil
+}
+
+//updateStarboard re-renders a starboard entry after its original message was edited or its link previews loaded. Updates that don't change the stored snapshot are skipped. Star footer and uploaded files of the starboard message are kept.
+func (se *StarboardEvent) updateStarboard() error {
+	snapshot := database.NewSnapshot(se.message)
+	if se.board.Snapshot != nil && se.board.Snapshot.Equal(snapshot) {
+		return nil
+	}
+
+	starboard, err := se.session.ChannelMessage(se.board.Starboard.ChannelID, se.board.Starboard.MessageID)
+	if err != nil {
+		return err
+	}
+
+	if len(starboard.Embeds) == 0 {
+		return nil
+	}
+
+	ch, err := se.session.Channel(se.message.ChannelID)
+	if err != nil {
+		return err
+	}
+
+	react := se.React
+	if react == nil {
+		react = &discordgo.MessageReactions{Emoji: &discordgo.Emoji{}}
+	}
+
+	se.skipFiles = true
+	msg, err := se.createEmbed(react, ch)
+	if err != nil {
+		return err
+	}
+	msg.Close()
+
+	var (
+		old   = starboard.Embeds[0]
+		embed = msg.Embed()
+	)
+
+	err = database.SetMessageSnapshot(se.board.Original, se.board.Board, snapshot)
+	if err != nil {
+		logrus.Warnln("database.SetMessageSnapshot():", err)
+	}
+
+	embed.Footer = old.Footer
+	if embed.Description == old.Description && imageURLs(msg.Embeds) == imageURLs(starboard.Embeds) {
+		return nil
+	}
+
+	logrus.Infof("Editing starboard (original updated) %v in channel %v", starboard.ID, starboard.ChannelID)
+	return editStarboardMessage(se.session, se.guild, se.board, msg.Embeds)
+}
+
+//imageURLs returns a comparable list of images of a starboard post.
+func imageURLs(embeds []*discordgo.MessageEmbed) string {
+	urls := make([]string, 0, len(embeds))
+	for _, embed := range embeds {
+		if embed.Image != nil {
+			urls = append(urls, embed.Image.URL)
+		}
+	}
+	return strings.Join(urls, " ")
+}
+
+//updateStars stores the current star count of a starboard entry for leaderboards.
+func (se *StarboardEvent) updateStars(count int) {
+	se.audit(database.AuditEdit, se.board, se.board.Stars, count, "")
+	err := database.SetMessageStars(se.board.Original, se.board.Board, count)
+	if err != nil {
+		logrus.Warnln("database.SetMessageStars():", err)
+	}
+}
+
+//triggeredBy returns an ID of a user who caused the event, empty if Eugen acted on its own.
+func (se *StarboardEvent) triggeredBy() string {
+	switch {
+	case se.addEvent != nil:
+		return se.addEvent.UserID
+	case se.removeEvent != nil:
+		return se.removeEvent.UserID
+	}
+	return se.userID
+}
+
+//audit records an action on a starboard entry to guild's audit log.
+func (se *StarboardEvent) audit(action string, entry *database.Message, before, after int, details string) {
+	a := database.NewAuditEntry(se.guild.ID, action, se.triggeredBy())
+	a.Board = entry.Board
+	a.Original = entry.Original
+	a.Starboard = entry.Starboard
+	a.Before = before
+	a.After = after
+	a.Details = details
+	utils.Audit(se.session, a)
 }
 
 func (se *StarboardEvent) deleteStarboard() error {
@@ -246,55 +1070,68 @@ func (se *StarboardEvent) deleteStarboard() error {
 		original = true
 	)
 
-	if se.board == nil {
+	boards, err := database.Reposts(se.deleteEvent.ChannelID, se.message.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(boards) == 0 {
 		original = false
 		board, err := database.RepostByStarboard(se.deleteEvent.ChannelID, se.message.ID)
 		if err != nil {
 			return err
 		}
 		if board != nil {
-			se.board = board
+			boards = append(boards, board)
 		} else {
-			return nil
+			return se.demoteDeletedFame()
 		}
 	}
 
-	if ch, ok := starboardQueue[*se.board.Original]; ok {
-		close(ch)
-		delete(starboardQueue, *se.board.Original)
-	}
-
-	err := database.DeleteMessage(se.board.Original)
-	if err != nil {
-		logrus.Warnln("database.DeleteMessage():", err)
-	}
-
-	logrus.Infof("Deleting starboard. ID: %v. Original: %v", se.deleteEvent.ID, original)
-	if original {
-		starboard, err := se.session.ChannelMessage(se.board.Starboard.ChannelID, se.board.Starboard.MessageID)
+	for _, board := range boards {
+		err := database.DeleteMessage(board.Original, board.Board)
 		if err != nil {
-			return err
+			logrus.Warnln("database.DeleteMessage():", err)
 		}
-		err = se.session.ChannelMessageDelete(starboard.ChannelID, starboard.ID)
-		if err != nil {
-			logrus.Warnln("se.session.ChannelMessageDelete():", err)
+
+		logrus.Infof("Deleting starboard. ID: %v. Board: %v. Original: %v", se.deleteEvent.ID, board.Board, original)
+		details := "Starboard message was deleted"
+		if original {
+			details = "Original message was deleted"
+		}
+		se.audit(database.AuditDelete, board, board.Stars, 0, details)
+
+		if original {
+			se.removeFame(board)
+			err := deleteStarboardMessage(se.session, se.guild, board)
+			if err != nil {
+				logrus.Warnln("deleteStarboardMessage():", err)
+			}
 		}
 	}
 	return nil
 }
 
-func (se *StarboardEvent) createEmbed(react *discordgo.MessageReactions, ch *discordgo.Channel) (*discordgo.MessageSend, *http.Response, error) {
+func (se *StarboardEvent) createEmbed(react *discordgo.MessageReactions, ch *discordgo.Channel) (*StarboardMessage, error) {
 	var (
 		eb         = embeds.NewBuilder()
-		resp       *http.Response
 		t, _       = se.message.Timestamp.Parse()
-		messageURL = fmt.Sprintf("https://discord.com/channels/%v/%v/%v", se.addEvent.GuildID, se.addEvent.ChannelID, se.message.ID)
-		msg        = &discordgo.MessageSend{}
+		messageURL = fmt.Sprintf("https://discord.com/channels/%v/%v/%v", se.guild.ID, se.message.ChannelID, se.message.ID)
+		msg        = &StarboardMessage{}
 		content    = se.message.Content
 		rx         = xurls.Strict()
 		URLs       = make([]*EugenURL, 0)
+		images     = make([]string, 0)
+		spoilered  = make(map[string]bool)
+		limit      = se.uploadLimit()
 	)
 
+	for _, spoiler := range utils.SpoilerRegex.FindAllStringSubmatch(content, -1) {
+		for _, uri := range rx.FindAllString(spoiler[1], -1) {
+			spoilered[uri] = true
+		}
+	}
+
 	for _, uri := range rx.FindAllString(content, -1) {
 		parsed, err := url.Parse(uri)
 		if err != nil {
@@ -302,7 +1139,8 @@ func (se *StarboardEvent) createEmbed(react *discordgo.MessageReactions, ch *dis
 		}
 
 		eu := &EugenURL{
-			URL: parsed,
+			URL:     parsed,
+			Spoiler: spoilered[uri],
 		}
 
 		switch {
@@ -325,20 +1163,10 @@ func (se *StarboardEvent) createEmbed(react *discordgo.MessageReactions, ch *dis
 	eb.Color(int(se.guild.EmbedColour))
 	eb.Timestamp(t)
 	eb.AddField("Original message", fmt.Sprintf("[Click here desu~](%v)", messageURL), true)
-	if se.guild.IsGuildEmoji() {
-		text := fmt.Sprintf("%v", react.Count)
-		if se.selfstar && se.guild.Selfstar {
-			text += " | self-starred"
-		}
-
-		eb.Footer(text, emojiURL(react.Emoji))
+	if se.target.IsGuildEmoji() {
+		eb.Footer(se.footerText(react.Count), emojiURL(react.Emoji))
 	} else {
-		text := fmt.Sprintf("%v %v", "⭐", react.Count)
-		if se.selfstar && se.guild.Selfstar {
-			text += " | self-starred"
-		}
-
-		eb.Footer(text, "")
+		eb.Footer(se.footerText(react.Count), "")
 	}
 
 	if ref := se.message.MessageReference; ref != nil {
@@ -348,103 +1176,97 @@ func (se *StarboardEvent) createEmbed(react *discordgo.MessageReactions, ch *dis
 
 	switch {
 	case len(se.message.Attachments) != 0:
-		var (
-			first = se.message.Attachments[0]
-			rest  = se.message.Attachments[1:]
-		)
+		for ind, a := range se.message.Attachments {
+			spoiler := utils.IsSpoilerFile(a.Filename)
+			if !spoiler && utils.ImageURLRegex.MatchString(a.URL) {
+				images = append(images, a.URL)
+				continue
+			}
 
-		if utils.ImageURLRegex.MatchString(first.URL) {
-			eb.Image(first.URL)
-		} else {
-			file, err := se.downloadFile(first.URL)
+			ok, err := se.uploadFile(msg, a.URL, spoiler, &limit)
 			if err != nil {
-				return nil, nil, err
+				msg.Close()
+				return nil, err
 			}
 
-			if file.Resp != nil {
-				resp = file.Resp
-				msg.Files = []*discordgo.File{
-					{
-						Name:   file.Name,
-						Reader: file.Resp.Body,
-					},
-				}
-			} else {
-				eb.AddField("Attachment", fmt.Sprintf("[Click here desu~](%v)", first.URL), true)
+			if !ok {
+				eb.AddField(fmt.Sprintf("Attachment %v", ind+1), spoilerLink(a.URL, spoiler), true)
 			}
 		}
 
-		for ind, a := range rest {
-			eb.AddField(fmt.Sprintf("Attachment %v", ind+2), fmt.Sprintf("[Click here desu~](%v)", a.URL), true)
+		//Image links posted along with attachments join the gallery.
+		var err error
+		content, images, err = se.contentImages(msg, URLs, content, images, &limit)
+		if err != nil {
+			msg.Close()
+			return nil, err
 		}
 	case len(URLs) != 0:
-		switch URLs[0].Type {
-		case ImageURL:
-			str := URLs[0].URL.String()
-			eb.Image(str)
-			content = strings.Replace(content, str, "", 1)
-		case VideoURL:
-			uri := URLs[0].URL.String()
+		switch {
+		case URLs[0].Type == ImageURL:
+			var err error
+			content, images, err = se.contentImages(msg, URLs, content, images, &limit)
+			if err != nil {
+				msg.Close()
+				return nil, err
+			}
+		case URLs[0].Type == VideoURL:
+			var (
+				uri     = URLs[0].URL.String()
+				spoiler = URLs[0].Spoiler
+			)
+
 			if strings.HasSuffix(uri, "gifv") {
 				uri = strings.Replace(uri, "gifv", "mp4", 1)
 			}
 
-			video, err := se.downloadFile(uri)
+			ok, err := se.uploadFile(msg, uri, spoiler, &limit)
 			if err != nil {
-				return nil, nil, err
+				msg.Close()
+				return nil, err
 			}
 
-			if video.Resp != nil {
-				resp = video.Resp
-				msg.Files = []*discordgo.File{
-					{
-						Name:   video.Name,
-						Reader: video.Resp.Body,
-					},
-				}
-			} else {
-				eb.AddField("Attachment", fmt.Sprintf("[Click here desu~](%v)", uri), true)
+			if !ok {
+				eb.AddField("Attachment", spoilerLink(uri, spoiler), true)
 			}
-			content = strings.Replace(content, uri, "", 1)
-		case TenorURL:
+
+			//Spoilered links stay in the description along with their markup.
+			if !spoiler {
+				content = strings.Replace(content, uri, "", 1)
+			}
+		case URLs[0].Spoiler:
+			//Previews of spoilered Tenor and Imgur links would reveal them.
+		case URLs[0].Type == TenorURL:
 			tenor := URLs[0].URL.String()
 			res, err := services.Tenor(tenor)
 			if err != nil {
 				logrus.Warn(err)
 			} else if len(res.Media) != 0 {
 				content = strings.ReplaceAll(content, tenor, "")
-				eb.Image(res.Media[0].MediumGIF.URL)
+				images = append(images, res.Media[0].MediumGIF.URL)
 			}
-		case ImgurURL:
+		case URLs[0].Type == ImgurURL:
 			if len(se.message.Embeds) != 0 {
 				emb := se.message.Embeds[0]
 				if emb.Video != nil {
-					file, err := se.downloadFile(emb.Video.URL)
+					ok, err := se.uploadFile(msg, emb.Video.URL, false, &limit)
 					if err != nil {
-						logrus.Warnln("se.dowloadFile():", err)
+						logrus.Warnln("se.uploadFile():", err)
 					}
 
-					if file.Resp != nil {
-						resp = file.Resp
-						msg.Files = []*discordgo.File{
-							{
-								Name:   file.Name,
-								Reader: file.Resp.Body,
-							},
-						}
-					} else if emb.Thumbnail != nil {
-						eb.Image(emb.Thumbnail.ProxyURL)
+					if !ok && emb.Thumbnail != nil {
+						images = append(images, emb.Thumbnail.ProxyURL)
 					}
 				} else if emb.Thumbnail != nil {
-					eb.Image(emb.Thumbnail.ProxyURL)
+					images = append(images, emb.Thumbnail.ProxyURL)
 				}
 			} else {
-				eb.Image(fmt.Sprintf("https://i.imgur.com/%v.png", URLs[0].URL.Path))
+				images = append(images, fmt.Sprintf("https://i.imgur.com/%v.png", URLs[0].URL.Path))
 			}
 
 			content = strings.Replace(content, URLs[0].URL.String(), "", 1)
 		}
-	case len(se.message.Embeds) != 0:
+	case len(se.message.Embeds) != 0 && !spoilered[se.message.Embeds[0].URL]:
 		emb := se.message.Embeds[0]
 		if emb.Footer != nil && strings.EqualFold(emb.Footer.Text, "twitter") {
 			if twitter := utils.TwitterRegex.FindString(se.message.Content); twitter != "" {
@@ -453,15 +1275,18 @@ func (se *StarboardEvent) createEmbed(react *discordgo.MessageReactions, ch *dis
 				eb.AddField("Twitter", fmt.Sprintf("[Click here desu~](%v)", twitter), true)
 			}
 
-			if emb.Image != nil {
-				eb.Image(emb.Image.URL)
+			//Tweets with several images come as several embeds sharing tweet's URL.
+			for _, e := range se.message.Embeds {
+				if e.Image != nil && (e == emb || e.URL == emb.URL) {
+					images = append(images, e.Image.URL)
+				}
 			}
 
 			if emb.Video != nil {
 				eb.AddField("Twitter video", fmt.Sprintf("[Click here desu~](%v)", emb.Video.URL), true)
 			}
 		} else if emb.Provider != nil && strings.EqualFold(emb.Provider.Name, "youtube") {
-			eb.Image(emb.Thumbnail.URL)
+			images = append(images, emb.Thumbnail.URL)
 			yt := utils.YoutubeRegex.FindString(content)
 			content = strings.Replace(content, yt, "", 1)
 
@@ -477,72 +1302,158 @@ func (se *StarboardEvent) createEmbed(react *discordgo.MessageReactions, ch *dis
 			}
 
 			if emb.Image != nil && emb.Image.URL != "" {
-				eb.Image(emb.Image.URL)
+				images = append(images, emb.Image.URL)
 			}
 		}
 	}
 
-	eb.Description(content)
-	msg.Embed = eb.Finalize()
-	return msg, resp, nil
-}
+	if len(images) != 0 {
+		eb.Image(images[0])
+	}
 
-func FindReact(message *discordgo.Message, emote string) *discordgo.MessageReactions {
-	for _, react := range message.Reactions {
-		if strings.ToLower(react.Emoji.APIName()) == strings.Trim(emote, "<:>") {
-			return react
+	for ind, image := range images {
+		if ind >= galleryLimit {
+			eb.AddField(fmt.Sprintf("Image %v", ind+1), fmt.Sprintf("[Click here desu~](%v)", image), true)
 		}
 	}
-	return nil
+
+	eb.Description(content)
+	msg.Embeds = []*discordgo.MessageEmbed{eb.Finalize()}
+	if len(images) > 1 {
+		msg.Embed().URL = messageURL
+		for ind := 1; ind < len(images) && ind < galleryLimit; ind++ {
+			msg.Embeds = append(msg.Embeds, &discordgo.MessageEmbed{
+				URL:   messageURL,
+				Image: &discordgo.MessageEmbedImage{URL: images[ind]},
+			})
+		}
+	}
+
+	return msg, nil
 }
 
-func (se *StarboardEvent) editStarboard(msg *discordgo.Message, react *discordgo.MessageReactions) *discordgo.MessageEmbed {
+//editStarboard updates star count in the footer of a starboard post. It returns nil if the count didn't change.
+func (se *StarboardEvent) editStarboard(msg *discordgo.Message, react *discordgo.MessageReactions) []*discordgo.MessageEmbed {
 	embed := msg.Embeds[0]
 
-	current, _ := strconv.Atoi(strings.Trim(embed.Footer.Text, "⭐ "))
-	if current == react.Count {
+	text := se.footerText(react.Count)
+	if embed.Footer.Text == text {
 		return nil
 	}
 
-	if se.guild.IsGuildEmoji() {
-		embed.Footer.Text = strconv.Itoa(react.Count)
-	} else {
-		embed.Footer.Text = fmt.Sprintf("⭐ %v", react.Count)
+	embed.Footer.Text = text
+	return msg.Embeds
+}
+
+//footerText returns a footer of a starboard post. With weighted voting raw reaction count is shown next to the score.
+func (se *StarboardEvent) footerText(count int) string {
+	text := strconv.Itoa(count)
+	if !se.target.IsGuildEmoji() {
+		text = "⭐ " + text
 	}
 
-	if se.selfstar && se.guild.Selfstar {
-		embed.Footer.Text += " | self-starred"
+	if se.weighted && se.raw != count {
+		text += fmt.Sprintf(" (%v reactions)", se.raw)
 	}
 
-	return embed
-}
+	if se.selfstar && se.target.Selfstar {
+		text += " | self-starred"
+	}
 
-func (se *StarboardEvent) downloadFile(uri string) (*StarboardFile, error) {
-	var (
-		file  = &StarboardFile{"", "", nil, nil}
-		limit = int64(8388608)
-	)
+	return text
+}
 
-	head, err := http.Head(uri)
+//uploadFile downloads a file and attaches it to the post if it fits into the remaining upload limit. Spoilered files stay spoilered.
+func (se *StarboardEvent) uploadFile(msg *StarboardMessage, uri string, spoiler bool, limit *int64) (bool, error) {
+	file, err := se.downloadFile(uri, *limit)
 	if err != nil {
-		return nil, err
+		return false, err
+	}
+
+	if file.URL != "" {
+		return false, nil
+	}
+
+	if se.skipFiles {
+		*limit -= file.Size
+		return true, nil
+	}
+
+	if spoiler {
+		file.Name = utils.SpoilerPrefix + strings.TrimPrefix(strings.TrimPrefix(file.Name, "/"), utils.SpoilerPrefix)
 	}
 
-	g, err := se.session.Guild(se.addEvent.GuildID)
+	*limit -= file.Size
+	msg.addFile(file)
+	return true, nil
+}
+
+//contentImages adds image links from message's content to the gallery and removes them from the description. Spoilered links are uploaded as spoilered files instead and keep their markup.
+func (se *StarboardEvent) contentImages(msg *StarboardMessage, URLs []*EugenURL, content string, images []string, limit *int64) (string, []string, error) {
+	for _, u := range URLs {
+		if u.Type != ImageURL {
+			continue
+		}
+
+		str := u.URL.String()
+		if u.Spoiler {
+			if _, err := se.uploadFile(msg, str, true, limit); err != nil {
+				return content, images, err
+			}
+			continue
+		}
+
+		images = append(images, str)
+		content = strings.Replace(content, str, "", 1)
+	}
+
+	return content, images, nil
+}
+
+//spoilerLink returns a link field value, hidden behind spoiler markup if needed.
+func spoilerLink(uri string, spoiler bool) string {
+	link := fmt.Sprintf("[Click here desu~](%v)", uri)
+	if spoiler {
+		return "||" + link + "||"
+	}
+	return link
+}
+
+//uploadLimit returns a file size limit of the guild, 8MB or 50MB depending on boost level.
+func (se *StarboardEvent) uploadLimit() int64 {
+	limit := int64(8388608)
+
+	g, err := se.session.Guild(se.guild.ID)
 	if err == nil {
 		if g.PremiumTier == discordgo.PremiumTier2 || g.PremiumTier == discordgo.PremiumTier3 {
 			limit = int64(52428800)
 		}
 	} else {
-		logrus.Warnf("downloadFile(): %v", err)
+		logrus.Warnf("uploadLimit(): %v", err)
 	}
 
-	//if Content-Length is larger than 8MB | 50MB depending on boost level
+	return limit
+}
+
+func (se *StarboardEvent) downloadFile(uri string, limit int64) (*StarboardFile, error) {
+	file := &StarboardFile{}
+
+	head, err := http.Head(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	//if Content-Length is larger than the remaining upload limit
 	if head.ContentLength >= limit {
 		file.URL = uri
 		return file, nil
 	}
 
+	if se.skipFiles {
+		file.Size = head.ContentLength
+		return file, nil
+	}
+
 	resp, err := http.Get(uri)
 	if err != nil {
 		return nil, err
@@ -555,11 +1466,26 @@ func (se *StarboardEvent) downloadFile(uri string) (*StarboardFile, error) {
 		file.Name = uri[begin:]
 	}
 
+	file.Size = head.ContentLength
 	file.Resp = resp
 
 	return file, nil
 }
 
+//boardEmoji returns a discordgo emoji of a board's emote.
+func boardEmoji(board *database.Board) *discordgo.Emoji {
+	if !board.IsGuildEmoji() {
+		return &discordgo.Emoji{Name: board.StarEmote}
+	}
+
+	parts := strings.Split(strings.Trim(board.StarEmote, "<>"), ":")
+	if len(parts) < 3 {
+		return &discordgo.Emoji{Name: board.StarEmote}
+	}
+
+	return &discordgo.Emoji{Name: parts[1], ID: parts[2]}
+}
+
 func emojiURL(emoji *discordgo.Emoji) string {
 	url := fmt.Sprintf("https://cdn.discordapp.com/emojis/%v.", emoji.ID)
 	if emoji.Animated {
@@ -16,6 +16,7 @@ func (t URLType) String() string {
 }
 
 type EugenURL struct {
-	URL  *url.URL
-	Type URLType
+	URL     *url.URL
+	Type    URLType
+	Spoiler bool
 }
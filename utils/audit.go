@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+)
+
+var auditTitles = map[string]string{
+	database.AuditCreate:      "⭐ Entry created",
+	database.AuditEdit:        "✏️ Entry edited",
+	database.AuditRemove:      "📉 Entry removed",
+	database.AuditDelete:      "🗑️ Entry deleted",
+	database.AuditStar:        "📌 Forced star",
+	database.AuditUnstar:      "📤 Forced unstar",
+	database.AuditFreeze:      "🧊 Entry frozen",
+	database.AuditUnfreeze:    "💧 Entry unfrozen",
+	database.AuditPromote:     "🏆 Entry promoted",
+	database.AuditDemote:      "📉 Entry demoted",
+	database.AuditSettings:    "⚙️ Setting changed",
+	database.AuditSetup:       "⚙️ Setup completed",
+	database.AuditBan:         "🚫 Channels banned",
+	database.AuditUnban:       "✅ Channels unbanned",
+	database.AuditBlacklist:   "🚫 Users blacklisted",
+	database.AuditUnblacklist: "✅ Users unblacklisted",
+}
+
+//Audit stores an audit entry and reports it to guild's audit channel if one is set.
+func Audit(s *discordgo.Session, entry *database.AuditEntry) {
+	err := database.InsertAudit(entry)
+	if err != nil {
+		log.Warnln("database.InsertAudit():", err)
+	}
+
+	guild, ok := database.GuildCache[entry.GuildID]
+	if !ok || guild.AuditChannel == "" {
+		return
+	}
+
+	_, err = s.ChannelMessageSendEmbed(guild.AuditChannel, AuditEmbed(entry))
+	if err != nil {
+		log.Warnf("Failed to send audit entry to %v: %v", guild.AuditChannel, err)
+	}
+}
+
+//AuditEmbed formats an audit entry for the audit channel.
+func AuditEmbed(entry *database.AuditEntry) *discordgo.MessageEmbed {
+	title, ok := auditTitles[entry.Action]
+	if !ok {
+		title = entry.Action
+	}
+
+	user := "Eugen"
+	if entry.UserID != "" {
+		user = fmt.Sprintf("<@%v>", entry.UserID)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:     title,
+		Color:     EmbedColor,
+		Timestamp: entry.CreatedAt.Format(time.RFC3339),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Triggered by", Value: user, Inline: true},
+		},
+	}
+
+	if entry.IsEntryAction() {
+		board := entry.Board
+		if board == "" {
+			board = database.DefaultBoardName
+		}
+
+		links := fmt.Sprintf("[Original](%v)", entry.Original.Link(entry.GuildID))
+		if entry.Starboard != nil {
+			links += fmt.Sprintf(" | [Starboard](%v)", entry.Starboard.Link(entry.GuildID))
+		}
+
+		embed.Fields = append(embed.Fields,
+			&discordgo.MessageEmbedField{Name: "Board", Value: board, Inline: true},
+			&discordgo.MessageEmbedField{Name: "Stars", Value: fmt.Sprintf("%v → %v", entry.Before, entry.After), Inline: true},
+			&discordgo.MessageEmbedField{Name: "Message", Value: links},
+		)
+	}
+
+	if entry.Details != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Details", Value: entry.Details})
+	}
+
+	return embed
+}
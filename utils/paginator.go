@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	previousPage = "⬅️"
+	nextPage     = "➡️"
+)
+
+//Paginator sends a list of embeds as a single message and lets its invoker flip pages with reactions.
+type Paginator struct {
+	Session   *discordgo.Session
+	ChannelID string
+	AuthorID  string
+	Pages     []*discordgo.MessageEmbed
+	Timeout   time.Duration
+	current   int
+	message   *discordgo.Message
+	mu        sync.Mutex
+}
+
+func NewPaginator(s *discordgo.Session, channelID, authorID string, pages []*discordgo.MessageEmbed) *Paginator {
+	return &Paginator{
+		Session:   s,
+		ChannelID: channelID,
+		AuthorID:  authorID,
+		Pages:     pages,
+		Timeout:   5 * time.Minute,
+	}
+}
+
+//Run sends the first page and listens to page reactions until timeout.
+func (p *Paginator) Run() error {
+	if len(p.Pages) == 0 {
+		return errors.New("nothing to show")
+	}
+
+	for ind, page := range p.Pages {
+		if len(p.Pages) > 1 {
+			page.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("Page %v of %v", ind+1, len(p.Pages))}
+		}
+	}
+
+	msg, err := p.Session.ChannelMessageSendEmbed(p.ChannelID, p.Pages[0])
+	if err != nil {
+		return err
+	}
+	p.message = msg
+
+	if len(p.Pages) == 1 {
+		return nil
+	}
+
+	p.Session.MessageReactionAdd(msg.ChannelID, msg.ID, previousPage)
+	p.Session.MessageReactionAdd(msg.ChannelID, msg.ID, nextPage)
+
+	remove := p.Session.AddHandler(p.onReaction)
+	time.AfterFunc(p.Timeout, func() {
+		remove()
+		p.Session.MessageReactionsRemoveAll(msg.ChannelID, msg.ID)
+	})
+
+	return nil
+}
+
+func (p *Paginator) onReaction(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+	if r.MessageID != p.message.ID || r.UserID == s.State.User.ID {
+		return
+	}
+
+	if r.UserID != p.AuthorID {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch r.Emoji.Name {
+	case previousPage:
+		if p.current == 0 {
+			p.current = len(p.Pages) - 1
+		} else {
+			p.current--
+		}
+	case nextPage:
+		if p.current == len(p.Pages)-1 {
+			p.current = 0
+		} else {
+			p.current++
+		}
+	default:
+		return
+	}
+
+	_, err := s.ChannelMessageEditEmbed(p.message.ChannelID, p.message.ID, p.Pages[p.current])
+	if err != nil {
+		log.Warnln("Paginator -> s.ChannelMessageEditEmbed(): ", err)
+	}
+
+	s.MessageReactionRemove(p.message.ChannelID, p.message.ID, r.Emoji.APIName(), r.UserID)
+}
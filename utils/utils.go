@@ -3,8 +3,11 @@ package utils
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/VTGare/Eugen/database"
@@ -22,6 +25,10 @@ var (
 	VideoURLRegex = regexp.MustCompile(`(?i)(?:http(?:s?):)(?:[/|.|\w|\s|-])*\.(mp4|webm|mov|gifv)(?:(?:\?|&)\w+=\w+)*`)
 	//YoutubeRegex ...
 	YoutubeRegex = regexp.MustCompile(`(?i)https?:\/\/(?:www\.)?youtu(?:be)?\.(?:com|be)\/(?:watch\?v=)?\S+`)
+	//MessageLinkRegex matches Discord message jump links
+	MessageLinkRegex = regexp.MustCompile(`https?://(?:(?:ptb|canary)\.)?discord(?:app)?\.com/channels/(\d+)/(\d+)/(\d+)`)
+	//SpoilerRegex matches spoiler markup
+	SpoilerRegex = regexp.MustCompile(`(?s)\|\|(.+?)\|\|`)
 	//NumRegex is a terrible number regex. Gonna replace it with better code.
 	NumRegex = regexp.MustCompile(`([0-9]+)`)
 	//EmojiRegex matches some Unicode emojis, it's not perfect but better than nothing
@@ -113,6 +120,159 @@ func IsValidChannel(s *discordgo.Session, guildID string, channelID string) bool
 	return false
 }
 
+//botUsers caches bot flags of users by their IDs. The flag never changes, so every user is looked up at most once.
+var botUsers sync.Map
+
+//IsBot checks if a user is a bot. Cached members and previously looked up users are checked first to avoid an API call.
+func IsBot(s *discordgo.Session, guildID, userID string) bool {
+	if bot, ok := botUsers.Load(userID); ok {
+		return bot.(bool)
+	}
+
+	if member, err := s.State.Member(guildID, userID); err == nil && member.User != nil {
+		botUsers.Store(userID, member.User.Bot)
+		return member.User.Bot
+	}
+
+	user, err := s.User(userID)
+	if err != nil {
+		logrus.Warnln("IsBot(): ", err)
+		return false
+	}
+
+	botUsers.Store(userID, user.Bot)
+	return user.Bot
+}
+
+//Thread channel types, discordgo doesn't know about them yet.
+const (
+	ChannelTypeNewsThread    discordgo.ChannelType = 10
+	ChannelTypePublicThread  discordgo.ChannelType = 11
+	ChannelTypePrivateThread discordgo.ChannelType = 12
+)
+
+//IsThread checks if a channel is a thread.
+func IsThread(ch *discordgo.Channel) bool {
+	return ch.Type == ChannelTypeNewsThread || ch.Type == ChannelTypePublicThread || ch.Type == ChannelTypePrivateThread
+}
+
+//NewRouteContext resolves a category, a thread parent and NSFW flag of a channel for routing rules. Threads inherit them from their parent channel.
+func NewRouteContext(s *discordgo.Session, ch *discordgo.Channel) (*database.RouteContext, error) {
+	ctx := &database.RouteContext{
+		ChannelID:  ch.ID,
+		CategoryID: ch.ParentID,
+		NSFW:       ch.NSFW,
+	}
+
+	if IsThread(ch) {
+		parent, err := s.Channel(ch.ParentID)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx.ThreadParentID = parent.ID
+		ctx.CategoryID = parent.ParentID
+		ctx.NSFW = parent.NSFW
+	}
+
+	return ctx, nil
+}
+
+//IsNSFWChannel checks if a channel is NSFW. Threads are NSFW if their parent channel is.
+func IsNSFWChannel(s *discordgo.Session, channelID string) (bool, error) {
+	ch, err := s.Channel(channelID)
+	if err != nil {
+		return false, err
+	}
+
+	route, err := NewRouteContext(s, ch)
+	if err != nil {
+		return false, err
+	}
+
+	return route.NSFW, nil
+}
+
+//SpoilerPrefix is a filename prefix Discord uses for spoilered attachments.
+const SpoilerPrefix = "SPOILER_"
+
+//IsSpoilerFile checks if an attachment's filename marks it as a spoiler.
+func IsSpoilerFile(filename string) bool {
+	return strings.HasPrefix(filename, SpoilerPrefix)
+}
+
+//HasSpoiler checks if a message has spoilered attachments or spoiler markup in its content.
+func HasSpoiler(msg *discordgo.Message) bool {
+	for _, a := range msg.Attachments {
+		if IsSpoilerFile(a.Filename) {
+			return true
+		}
+	}
+
+	return SpoilerRegex.MatchString(msg.Content)
+}
+
+//IsNotFound checks if Discord API responded with 404 Not Found.
+func IsNotFound(err error) bool {
+	var rest *discordgo.RESTError
+	if errors.As(err, &rest) {
+		return rest.Response != nil && rest.Response.StatusCode == http.StatusNotFound
+	}
+	return false
+}
+
+//FindReact returns message's reactions with a starboard emote.
+func FindReact(message *discordgo.Message, emote string) *discordgo.MessageReactions {
+	for _, react := range message.Reactions {
+		if strings.ToLower(react.Emoji.APIName()) == strings.Trim(emote, "<:>") {
+			return react
+		}
+	}
+	return nil
+}
+
+//ParseMessageLink returns guild, channel and message IDs from a message jump link.
+func ParseMessageLink(link string) (guildID, channelID, messageID string, ok bool) {
+	match := MessageLinkRegex.FindStringSubmatch(link)
+	if match == nil {
+		return "", "", "", false
+	}
+
+	return match[1], match[2], match[3], true
+}
+
+//ParseSince parses a relative time like 12h, 7d, 2w, 3m or 1y, or a date formatted as 2006-01-02, to a point in the past.
+func ParseSince(str string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", str); err == nil {
+		return t, nil
+	}
+
+	if len(str) < 2 {
+		return time.Time{}, fmt.Errorf("unable to parse %v to a time", str)
+	}
+
+	num, err := strconv.Atoi(str[:len(str)-1])
+	if err != nil || num < 0 {
+		return time.Time{}, fmt.Errorf("unable to parse %v to a time", str)
+	}
+
+	now := time.Now()
+	switch str[len(str)-1] {
+	case 'h':
+		return now.Add(-time.Duration(num) * time.Hour), nil
+	case 'd':
+		return now.AddDate(0, 0, -num), nil
+	case 'w':
+		return now.AddDate(0, 0, -num*7), nil
+	case 'm':
+		return now.AddDate(0, -num, 0), nil
+	case 'y':
+		return now.AddDate(-num, 0, 0), nil
+	}
+
+	return time.Time{}, fmt.Errorf("unable to parse %v to a time", str)
+}
+
 //FormatBool returns human-readable representation of boolean
 func FormatBool(b bool) string {
 	if b {
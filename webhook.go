@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+	"github.com/sirupsen/logrus"
+)
+
+//webhookName is a name of webhooks created by Eugen. It's also used when author's name can't be a webhook username.
+const webhookName = "Eugen"
+
+//boardWebhook returns Eugen's webhook in a starboard channel, creating one if the channel doesn't have it yet.
+func boardWebhook(s *discordgo.Session, guild *database.Guild, channelID string) (*database.Webhook, error) {
+	if wh := guild.FindWebhook(channelID); wh != nil {
+		return wh, nil
+	}
+
+	created, err := s.WebhookCreate(channelID, webhookName, "")
+	if err != nil {
+		return nil, err
+	}
+
+	wh := &database.Webhook{
+		ChannelID: channelID,
+		ID:        created.ID,
+		Token:     created.Token,
+	}
+
+	err = database.SetWebhook(guild.ID, wh)
+	if err != nil {
+		return nil, err
+	}
+
+	return wh, nil
+}
+
+//webhookUsername returns author's name in a form Discord accepts as a webhook username.
+func webhookUsername(msg *discordgo.Message) string {
+	name := msg.Author.Username
+	if msg.Member != nil && msg.Member.Nick != "" {
+		name = msg.Member.Nick
+	}
+
+	//Discord rejects webhook usernames containing "clyde".
+	if strings.Contains(strings.ToLower(name), "clyde") {
+		return webhookName
+	}
+
+	if runes := []rune(name); len(runes) > 80 {
+		name = string(runes[:80])
+	}
+
+	return name
+}
+
+//sendWebhookMessage posts a message through channel's managed webhook and returns it. If the webhook was deleted, a new one is created.
+func sendWebhookMessage(s *discordgo.Session, guild *database.Guild, channelID string, params *discordgo.WebhookParams, files []*discordgo.File) (*discordgo.Message, *database.Webhook, error) {
+	contentType, body, err := messageBody(params, files)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var response []byte
+	for retry := 0; retry < 2; retry++ {
+		var wh *database.Webhook
+		wh, err = boardWebhook(s, guild, channelID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		uri := discordgo.EndpointWebhookToken(wh.ID, wh.Token) + "?wait=true"
+		response, err = s.RequestWithLockedBucket("POST", uri, contentType, body, s.Ratelimiter.LockBucket(discordgo.EndpointWebhookToken(wh.ID, "")), 0)
+		if err == nil {
+			msg := &discordgo.Message{}
+			err = json.Unmarshal(response, msg)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			return msg, wh, nil
+		}
+
+		if !utils.IsNotFound(err) {
+			return nil, nil, err
+		}
+
+		logrus.Infof("Webhook %v in channel %v is gone. Creating a new one.", wh.ID, channelID)
+		err = database.RemoveWebhook(guild.ID, channelID)
+		if err != nil {
+			return nil, nil, err
+		}
+		guild = database.GuildCache[guild.ID]
+	}
+
+	return nil, nil, err
+}
+
+//editStarboardMessage edits embeds of a starboard message. Messages posted by a webhook can only be edited through it.
+func editStarboardMessage(s *discordgo.Session, guild *database.Guild, entry *database.Message, embeds []*discordgo.MessageEmbed) error {
+	if entry.WebhookID == "" {
+		return editChannelMessage(s, entry.Starboard.ChannelID, entry.Starboard.MessageID, embeds)
+	}
+
+	wh := guild.WebhookByID(entry.WebhookID)
+	if wh == nil {
+		return fmt.Errorf("webhook %v of starboard %v is gone", entry.WebhookID, entry.Starboard.MessageID)
+	}
+
+	uri := discordgo.EndpointWebhookToken(wh.ID, wh.Token) + "/messages/" + entry.Starboard.MessageID
+	_, err := s.RequestWithBucketID("PATCH", uri, map[string]interface{}{
+		"embeds": embeds,
+	}, discordgo.EndpointWebhookToken(wh.ID, "")+"/messages/")
+	return err
+}
+
+//deleteStarboardMessage deletes a starboard message, through its webhook if it was posted by one.
+func deleteStarboardMessage(s *discordgo.Session, guild *database.Guild, entry *database.Message) error {
+	if wh := guild.WebhookByID(entry.WebhookID); wh != nil {
+		uri := discordgo.EndpointWebhookToken(wh.ID, wh.Token) + "/messages/" + entry.Starboard.MessageID
+		_, err := s.RequestWithBucketID("DELETE", uri, nil, discordgo.EndpointWebhookToken(wh.ID, "")+"/messages/")
+		if err == nil || !utils.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return s.ChannelMessageDelete(entry.Starboard.ChannelID, entry.Starboard.MessageID)
+}